@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/veritas-protocol/veritas/services/gateway/middleware"
+	"github.com/veritas-protocol/veritas/services/pkg/didkey"
+	"github.com/veritas-protocol/veritas/services/pkg/zkp"
+)
+
+// attestationKeyID identifies the claim attestation key in the gateway's DID
+// document.
+const attestationKeyID = gatewayDID + "#attestation-key"
+
+// issuedCredential is a credential the gateway issued, with the caller that
+// issued it and what the gateway needs to prove claims about it later.
+type issuedCredential struct {
+	IssueCredentialResponse
+	owner string
+
+	kycLevel *zkp.ThresholdSecret
+}
+
+// commitKYCLevel commits to the credential's kyc_level claim, so proofs about
+// the level can later be attested against the commitment.
+func (h *GatewayHandler) commitKYCLevel(cred *issuedCredential, level int) error {
+	secret, err := zkp.NewThresholdSecret(level)
+	if err != nil {
+		return fmt.Errorf("claims.kyc_level: %w", err)
+	}
+
+	cred.kycLevel = &secret
+	return nil
+}
+
+// generateKYCLevelProof proves that the kyc_level claim of the credential
+// params["credential_id"] is at least params["min_level"] without revealing
+// the level itself. The attestation is signed for params["challenge"], the
+// challenge the verifier issued, so the proof cannot be replayed to it with
+// another challenge. Only the caller that issued the credential may prove
+// claims about it.
+func (h *GatewayHandler) generateKYCLevelProof(w http.ResponseWriter, r *http.Request, params map[string]interface{}) {
+	credID, _ := params["credential_id"].(string)
+	if credID == "" {
+		writeError(w, http.StatusBadRequest, "params.credential_id is required")
+		return
+	}
+	minLevel, err := intParam(params, "min_level")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	challenge, _ := params["challenge"].(string)
+	if challenge == "" {
+		writeError(w, http.StatusBadRequest, "params.challenge is required; obtain one from the verifier")
+		return
+	}
+
+	h.mu.RLock()
+	cred, exists := h.credentials[credID]
+	h.mu.RUnlock()
+	if !exists || cred.owner != middleware.APIKeyFromContext(r.Context()) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("credential %s not found", credID))
+		return
+	}
+	if cred.kycLevel == nil {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("credential %s has no kyc_level claim", credID))
+		return
+	}
+
+	attestation, err := zkp.SignAttestation(zkp.Attestation{
+		CredentialID: cred.CredentialID,
+		Issuer:       gatewayDID,
+		Subject:      cred.Subject,
+		Claim:        "kyc_level",
+		Commitment:   cred.kycLevel.Commitment(),
+		Challenge:    challenge,
+	}, attestationKeyID, h.signingKey)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to sign attestation: %v", err))
+		return
+	}
+
+	proof, err := zkp.ProveThreshold(*cred.kycLevel, attestation, minLevel)
+	if errors.Is(err, zkp.ErrThresholdNotMet) {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("kyc_level does not satisfy min_level %d", minLevel))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"proof_type": "kyc_level",
+		"status":     "generated",
+		"proof":      proof,
+	})
+}
+
+// DIDDocument returns the gateway's DID document, which publishes the key
+// that claim attestations are signed with.
+func (h *GatewayHandler) DIDDocument() didkey.Document {
+	return didkey.NewDocument(gatewayDID, attestationKeyID, h.signingKey.Public().(ed25519.PublicKey))
+}
+
+// HandleDIDDocument handles GET /api/v1/did-document.
+func (h *GatewayHandler) HandleDIDDocument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.DIDDocument())
+}
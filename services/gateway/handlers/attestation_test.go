@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/veritas-protocol/veritas/services/gateway/middleware"
+	"github.com/veritas-protocol/veritas/services/pkg/didkey"
+	"github.com/veritas-protocol/veritas/services/pkg/zkp"
+)
+
+// serveAs calls handler behind API key authentication as apiKey.
+func serveAs(t *testing.T, handler http.HandlerFunc, apiKey, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	auth := middleware.NewAuthMiddlewareWithKeys([]string{"key-alice", "key-bob"})
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", apiKey)
+	rec := httptest.NewRecorder()
+	auth.AuthenticateFunc(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+// issueKYCCredential issues a credential as apiKey with the given claims and
+// returns its ID.
+func issueKYCCredential(t *testing.T, h *GatewayHandler, apiKey, claims string) string {
+	t.Helper()
+	body := `{"subject_did":"did:veritas:holder","credential_type":["KYCCredential"],"claims":` + claims + `}`
+	rec := serveAs(t, h.HandleIssueCredential, apiKey, http.MethodPost, "/api/v1/credentials/issue", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("issue status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp IssueCredentialResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.CredentialID
+}
+
+func TestGenerateKYCLevelProof(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewGatewayHandlerWithKey(key)
+
+	level3 := issueKYCCredential(t, h, "key-alice", `{"kyc_level":3}`)
+	level1 := issueKYCCredential(t, h, "key-alice", `{"kyc_level":1}`)
+	noLevel := issueKYCCredential(t, h, "key-alice", `{"country":"BR"}`)
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		params     string
+		wantStatus int
+	}{
+		{name: "level 3 satisfies min_level 2", apiKey: "key-alice", params: `{"credential_id":"` + level3 + `","min_level":2,"challenge":"ch-1"}`, wantStatus: http.StatusOK},
+		{name: "level 1 fails min_level 2", apiKey: "key-alice", params: `{"credential_id":"` + level1 + `","min_level":2,"challenge":"ch-1"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "caller-stated level is ignored", apiKey: "key-alice", params: `{"credential_id":"` + level1 + `","min_level":2,"kyc_level":5,"challenge":"ch-1"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "another caller's credential", apiKey: "key-bob", params: `{"credential_id":"` + level3 + `","min_level":2,"challenge":"ch-1"}`, wantStatus: http.StatusNotFound},
+		{name: "unknown credential", apiKey: "key-alice", params: `{"credential_id":"gw-vc-999999","min_level":2,"challenge":"ch-1"}`, wantStatus: http.StatusNotFound},
+		{name: "credential without kyc_level", apiKey: "key-alice", params: `{"credential_id":"` + noLevel + `","min_level":2,"challenge":"ch-1"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "missing credential_id", apiKey: "key-alice", params: `{"min_level":2,"challenge":"ch-1"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing min_level", apiKey: "key-alice", params: `{"credential_id":"` + level3 + `","challenge":"ch-1"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing challenge", apiKey: "key-alice", params: `{"credential_id":"` + level3 + `","min_level":2}`, wantStatus: http.StatusBadRequest},
+	}
+
+	issuerKey, err := h.DIDDocument().AssertionKey(attestationKeyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"proof_type":"kyc_level","params":` + tt.params + `}`
+			rec := serveAs(t, h.HandleGenerateProof, tt.apiKey, http.MethodPost, "/api/v1/proofs/generate", body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Proof zkp.ThresholdProof `json:"proof"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(rec.Body.String(), `"kyc_level":3`) {
+				t.Error("proof reveals the level")
+			}
+			attestation, err := zkp.VerifyThreshold(resp.Proof, issuerKey)
+			if err != nil {
+				t.Fatalf("proof does not verify against the published key: %v", err)
+			}
+			if attestation.CredentialID != level3 || attestation.Claim != "kyc_level" || attestation.Challenge != "ch-1" || resp.Proof.Min != 2 {
+				t.Errorf("proof = %+v, attestation = %+v", resp.Proof, attestation)
+			}
+		})
+	}
+}
+
+func TestIssueCredentialRejectsInvalidKYCLevel(t *testing.T) {
+	for _, claims := range []string{`{"kyc_level":"3"}`, `{"kyc_level":-1}`, `{"kyc_level":2.5}`, `{"kyc_level":100000}`} {
+		t.Run(claims, func(t *testing.T) {
+			h := NewGatewayHandler()
+			body := `{"subject_did":"did:veritas:holder","credential_type":["KYCCredential"],"claims":` + claims + `}`
+			rec := serveAs(t, h.HandleIssueCredential, "key-alice", http.MethodPost, "/api/v1/credentials/issue", body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestHandleDIDDocument(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewGatewayHandlerWithKey(key)
+
+	rec := httptest.NewRecorder()
+	h.HandleDIDDocument(rec, httptest.NewRequest(http.MethodGet, "/api/v1/did-document", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var doc didkey.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	pub, err := doc.AssertionKey(attestationKeyID)
+	if err != nil {
+		t.Fatalf("AssertionKey: %v", err)
+	}
+	if !pub.Equal(key.Public()) {
+		t.Error("DID document publishes a different key")
+	}
+}
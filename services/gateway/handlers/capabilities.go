@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CredentialSchema describes a credential schema offered by the issuer.
+type CredentialSchema struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Capabilities is the document returned by GET /api/v1/capabilities.
+type Capabilities struct {
+	ProofTypes []string           `json:"proof_types"`
+	Schemas    []CredentialSchema `json:"schemas"`
+}
+
+// ProofTypeLister reports the proof types the verifier accepts.
+type ProofTypeLister interface {
+	ListProofTypes(ctx context.Context) ([]string, error)
+}
+
+// SchemaLister reports the credential schemas the issuer offers.
+type SchemaLister interface {
+	ListSchemas(ctx context.Context) ([]CredentialSchema, error)
+}
+
+// VerifierClient reads capabilities from the Veritas Verifier API.
+type VerifierClient struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewVerifierClient creates a client for the verifier at baseURL.
+func NewVerifierClient(baseURL string) *VerifierClient {
+	return &VerifierClient{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ListProofTypes fetches GET /api/v1/proof-types.
+func (c *VerifierClient) ListProofTypes(ctx context.Context) ([]string, error) {
+	var body struct {
+		ProofTypes []string `json:"proof_types"`
+	}
+	if err := getJSON(ctx, c.Client, c.BaseURL+"/api/v1/proof-types", &body); err != nil {
+		return nil, fmt.Errorf("list proof types: %w", err)
+	}
+	return body.ProofTypes, nil
+}
+
+// IssuerClient reads capabilities from the Veritas Issuer API.
+type IssuerClient struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewIssuerClient creates a client for the issuer at baseURL.
+func NewIssuerClient(baseURL string) *IssuerClient {
+	return &IssuerClient{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ListSchemas fetches GET /api/v1/schemas.
+func (c *IssuerClient) ListSchemas(ctx context.Context) ([]CredentialSchema, error) {
+	var body struct {
+		Schemas []CredentialSchema `json:"schemas"`
+	}
+	if err := getJSON(ctx, c.Client, c.BaseURL+"/api/v1/schemas", &body); err != nil {
+		return nil, fmt.Errorf("list schemas: %w", err)
+	}
+	return body.Schemas, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// CapabilitiesHandler serves the proof types and credential schemas the
+// ecosystem supports, so a wallet can learn both in one call.
+type CapabilitiesHandler struct {
+	proofTypes ProofTypeLister
+	schemas    SchemaLister
+}
+
+// NewCapabilitiesHandler creates a CapabilitiesHandler backed by the given
+// verifier and issuer clients.
+func NewCapabilitiesHandler(proofTypes ProofTypeLister, schemas SchemaLister) *CapabilitiesHandler {
+	return &CapabilitiesHandler{proofTypes: proofTypes, schemas: schemas}
+}
+
+// HandleCapabilities handles GET /api/v1/capabilities.
+func (h *CapabilitiesHandler) HandleCapabilities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	proofTypes, err := h.proofTypes.ListProofTypes(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	schemas, err := h.schemas.ListSchemas(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	if proofTypes == nil {
+		proofTypes = []string{}
+	}
+	if schemas == nil {
+		schemas = []CredentialSchema{}
+	}
+
+	writeJSON(w, http.StatusOK, Capabilities{
+		ProofTypes: proofTypes,
+		Schemas:    schemas,
+	})
+}
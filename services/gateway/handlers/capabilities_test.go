@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type fakeProofTypeLister struct {
+	types []string
+	err   error
+}
+
+func (f fakeProofTypeLister) ListProofTypes(ctx context.Context) ([]string, error) {
+	return f.types, f.err
+}
+
+type fakeSchemaLister struct {
+	schemas []CredentialSchema
+	err     error
+}
+
+func (f fakeSchemaLister) ListSchemas(ctx context.Context) ([]CredentialSchema, error) {
+	return f.schemas, f.err
+}
+
+func TestHandleCapabilities(t *testing.T) {
+	kyc := CredentialSchema{ID: "kyc-v1", Name: "KYC", Version: "1.0"}
+	unavailable := errors.New("upstream unavailable")
+
+	tests := []struct {
+		name       string
+		proofTypes fakeProofTypeLister
+		schemas    fakeSchemaLister
+		wantStatus int
+		want       Capabilities
+	}{
+		{
+			name:       "both sections",
+			proofTypes: fakeProofTypeLister{types: []string{"age_over_18", "kyc_level"}},
+			schemas:    fakeSchemaLister{schemas: []CredentialSchema{kyc}},
+			wantStatus: http.StatusOK,
+			want:       Capabilities{ProofTypes: []string{"age_over_18", "kyc_level"}, Schemas: []CredentialSchema{kyc}},
+		},
+		{
+			name:       "empty sections are arrays",
+			wantStatus: http.StatusOK,
+			want:       Capabilities{ProofTypes: []string{}, Schemas: []CredentialSchema{}},
+		},
+		{
+			name:       "verifier failure",
+			proofTypes: fakeProofTypeLister{err: unavailable},
+			schemas:    fakeSchemaLister{schemas: []CredentialSchema{kyc}},
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "issuer failure",
+			proofTypes: fakeProofTypeLister{types: []string{"kyc_level"}},
+			schemas:    fakeSchemaLister{err: unavailable},
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCapabilitiesHandler(tt.proofTypes, tt.schemas)
+			rec := httptest.NewRecorder()
+			h.HandleCapabilities(rec, httptest.NewRequest(http.MethodGet, "/api/v1/capabilities", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got Capabilities
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("capabilities = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapabilitiesClientsReportUpstreamErrors(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusInternalServerError)
+	}))
+	defer upstream.Close()
+
+	h := NewCapabilitiesHandler(NewVerifierClient(upstream.URL), NewIssuerClient(upstream.URL))
+	rec := httptest.NewRecorder()
+	h.HandleCapabilities(rec, httptest.NewRequest(http.MethodGet, "/api/v1/capabilities", nil))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusBadGateway, rec.Body)
+	}
+}
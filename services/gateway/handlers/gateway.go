@@ -2,12 +2,17 @@
 package handlers
 
 import (
-	"encoding/json"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 	"sync"
 	"time"
+
+	"github.com/veritas-protocol/veritas/services/gateway/middleware"
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+	"github.com/veritas-protocol/veritas/services/pkg/httpjson"
 )
 
 // IssueCredentialRequest represents a request to issue a credential.
@@ -44,20 +49,64 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
+// EventNotifier is notified of events caused by an API key holder.
+type EventNotifier interface {
+	Notify(owner, event string, data interface{})
+}
+
+// gatewayDID identifies the gateway as the issuer of its credentials.
+const gatewayDID = "did:veritas:key:gateway"
+
 // GatewayHandler handles gateway-related API endpoints.
 type GatewayHandler struct {
 	mu          sync.RWMutex
-	credentials map[string]*IssueCredentialResponse
+	credentials map[string]*issuedCredential
 	counter     int
+	signingKey  ed25519.PrivateKey
+	notifier    EventNotifier
+	resolver    DIDResolver
+	clock       clock.Clock
 }
 
-// NewGatewayHandler creates a new GatewayHandler.
+// NewGatewayHandler creates a new GatewayHandler with a freshly generated
+// attestation signing key. The key does not survive a restart, so verifiers
+// cannot be configured to trust it ahead of time; use
+// NewGatewayHandlerWithKey with a key from didkey.LoadPrivateKey to serve
+// kyc_level proofs.
 func NewGatewayHandler() *GatewayHandler {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("gateway: failed to generate signing key: %v", err))
+	}
+	return NewGatewayHandlerWithKey(priv)
+}
+
+// NewGatewayHandlerWithKey creates a new GatewayHandler that signs claim
+// attestations with the given key.
+func NewGatewayHandlerWithKey(key ed25519.PrivateKey) *GatewayHandler {
 	return &GatewayHandler{
-		credentials: make(map[string]*IssueCredentialResponse),
+		credentials: make(map[string]*issuedCredential),
+		signingKey:  key,
+		clock:       clock.New(),
 	}
 }
 
+// UseClock sets the time source used for credential timestamps.
+func (h *GatewayHandler) UseClock(c clock.Clock) {
+	h.clock = c
+}
+
+// UseNotifier sets the notifier told about credentials issued by each caller.
+func (h *GatewayHandler) UseNotifier(n EventNotifier) {
+	h.notifier = n
+}
+
+// UseResolver sets the resolver that GET /api/v1/identity/:did forwards to.
+// Without one, resolution answers 503.
+func (h *GatewayHandler) UseResolver(r DIDResolver) {
+	h.resolver = r
+}
+
 // HandleIssueCredential handles POST /api/v1/credentials/issue.
 func (h *GatewayHandler) HandleIssueCredential(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -66,8 +115,8 @@ func (h *GatewayHandler) HandleIssueCredential(w http.ResponseWriter, r *http.Re
 	}
 
 	var req IssueCredentialRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 
@@ -80,28 +129,50 @@ func (h *GatewayHandler) HandleIssueCredential(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	now := time.Now().UTC()
+	var kycLevel *int
+	if _, ok := req.Claims["kyc_level"]; ok {
+		level, err := intParam(req.Claims, "kyc_level")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "claims.kyc_level must be a non-negative integer")
+			return
+		}
+		kycLevel = &level
+	}
+
+	now := h.clock.Now().UTC()
 
 	h.mu.Lock()
 	h.counter++
 	credID := fmt.Sprintf("gw-vc-%06d", h.counter)
-	h.credentials[credID] = &IssueCredentialResponse{
-		CredentialID: credID,
-		Issuer:       "did:veritas:key:gateway",
-		Subject:      req.SubjectDID,
-		Status:       "issued",
-		CreatedAt:    now,
-	}
 	h.mu.Unlock()
 
 	resp := IssueCredentialResponse{
 		CredentialID: credID,
-		Issuer:       "did:veritas:key:gateway",
+		Issuer:       gatewayDID,
 		Subject:      req.SubjectDID,
 		Status:       "issued",
 		CreatedAt:    now,
 	}
 
+	issued := &issuedCredential{
+		IssueCredentialResponse: resp,
+		owner:                   middleware.APIKeyFromContext(r.Context()),
+	}
+	if kycLevel != nil {
+		if err := h.commitKYCLevel(issued, *kycLevel); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	h.mu.Lock()
+	h.credentials[credID] = issued
+	h.mu.Unlock()
+
+	if h.notifier != nil {
+		h.notifier.Notify(middleware.APIKeyFromContext(r.Context()), EventCredentialIssued, resp)
+	}
+
 	writeJSON(w, http.StatusCreated, resp)
 }
 
@@ -113,8 +184,8 @@ func (h *GatewayHandler) HandleVerifyCredential(w http.ResponseWriter, r *http.R
 	}
 
 	var req VerifyCredentialRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 
@@ -137,8 +208,8 @@ func (h *GatewayHandler) HandleGenerateProof(w http.ResponseWriter, r *http.Requ
 	}
 
 	var req GenerateProofRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 
@@ -153,6 +224,11 @@ func (h *GatewayHandler) HandleGenerateProof(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if req.ProofType == "kyc_level" {
+		h.generateKYCLevelProof(w, r, req.Params)
+		return
+	}
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"proof_type": req.ProofType,
 		"status":     "generated",
@@ -160,29 +236,52 @@ func (h *GatewayHandler) HandleGenerateProof(w http.ResponseWriter, r *http.Requ
 	})
 }
 
-// HandleResolve handles GET /api/v1/identity/:did.
+// HandleResolve handles GET /api/v1/identity/:did by forwarding to the
+// configured resolver, which dispatches on the DID's method.
 func (h *GatewayHandler) HandleResolve(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
-	did := strings.TrimPrefix(r.URL.Path, "/api/v1/identity/")
-	if did == "" {
-		writeError(w, http.StatusBadRequest, "DID is required")
+	did, err := httpjson.PathParam(r.URL.Path, "/api/v1/identity/", "DID")
+	if err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"did":    did,
-		"status": "resolution delegated to Veritas node",
-	})
+	if h.resolver == nil {
+		writeError(w, http.StatusServiceUnavailable, "DID resolution is not configured")
+		return
+	}
+
+	result, err := h.resolver.ResolveDID(r.Context(), did)
+	if errors.Is(err, ErrDIDNotFound) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("DID %s not found", did))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// intParam reads a non-negative integer parameter decoded from JSON.
+func intParam(params map[string]interface{}, name string) (int, error) {
+	v, ok := params[name].(float64)
+	if !ok {
+		return 0, fmt.Errorf("params.%s is required and must be a number", name)
+	}
+	if v < 0 || v != float64(int(v)) {
+		return 0, fmt.Errorf("params.%s must be a non-negative integer", name)
+	}
+	return int(v), nil
 }
 
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+	httpjson.Write(w, status, data)
 }
 
 func writeError(w http.ResponseWriter, status int, message string) {
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxResolutionBytes bounds the registry response RegistryClient accepts.
+const maxResolutionBytes = 1 << 20
+
+// ErrDIDNotFound is returned by a DIDResolver when the DID does not exist.
+var ErrDIDNotFound = errors.New("DID not found")
+
+// DIDResolver resolves a DID to the registry's resolution result.
+type DIDResolver interface {
+	ResolveDID(ctx context.Context, did string) (map[string]interface{}, error)
+}
+
+// RegistryClient resolves DIDs through the Veritas Registry API, which
+// dispatches on the DID's method and falls back to its local store for
+// did:veritas.
+type RegistryClient struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewRegistryClient creates a client for the registry at baseURL.
+func NewRegistryClient(baseURL string) *RegistryClient {
+	return &RegistryClient{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// ResolveDID fetches GET /api/v1/dids/:did.
+func (c *RegistryClient) ResolveDID(ctx context.Context, did string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/api/v1/dids/"+url.PathEscape(did), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", did, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrDIDNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("resolve %s: registry returned %d", did, resp.StatusCode)
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResolutionBytes)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", did, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// newFakeRegistry serves GET /api/v1/dids/:did for the given documents and
+// answers 500 for did:veritas:broken.
+func newFakeRegistry(t *testing.T, docs map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		did := r.URL.Path[len("/api/v1/dids/"):]
+		if did == "did:veritas:broken" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		doc, ok := docs[did]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"did": did, "document": json.RawMessage(doc)})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHandleResolve(t *testing.T) {
+	registry := newFakeRegistry(t, map[string]string{
+		"did:veritas:alice":          `{"id":"did:veritas:alice"}`,
+		"did:web:example.com%3A8443": `{"id":"did:web:example.com%3A8443"}`,
+	})
+
+	tests := []struct {
+		name       string
+		did        string
+		noResolver bool
+		wantStatus int
+	}{
+		{name: "local DID", did: "did:veritas:alice", wantStatus: http.StatusOK},
+		{name: "did:web with a port", did: "did:web:example.com%3A8443", wantStatus: http.StatusOK},
+		{name: "unknown DID", did: "did:veritas:bob", wantStatus: http.StatusNotFound},
+		{name: "registry failure", did: "did:veritas:broken", wantStatus: http.StatusBadGateway},
+		{name: "no resolver configured", did: "did:veritas:alice", noResolver: true, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewGatewayHandler()
+			if !tt.noResolver {
+				h.UseResolver(NewRegistryClient(registry.URL))
+			}
+
+			rec := httptest.NewRecorder()
+			h.HandleResolve(rec, httptest.NewRequest(http.MethodGet, "/api/v1/identity/"+url.PathEscape(tt.did), nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				DID      string                 `json:"did"`
+				Document map[string]interface{} `json:"document"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.DID != tt.did || resp.Document["id"] != tt.did {
+				t.Errorf("resolved %s to %+v", tt.did, resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/gateway/middleware"
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+	"github.com/veritas-protocol/veritas/services/pkg/httpjson"
+	"github.com/veritas-protocol/veritas/services/pkg/netguard"
+)
+
+const (
+	// EventCredentialIssued fires when the caller issues a credential.
+	EventCredentialIssued = "credential.issued"
+
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of the delivery body
+	// keyed with the subscription secret, prefixed with "sha256=".
+	WebhookSignatureHeader = "X-Veritas-Signature"
+
+	// WebhookEventHeader carries the event name of a delivery.
+	WebhookEventHeader = "X-Veritas-Event"
+)
+
+// knownEvents lists the events a subscription may filter on.
+var knownEvents = map[string]bool{
+	EventCredentialIssued: true,
+}
+
+// CreateWebhookRequest represents a request to subscribe a webhook.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events"`
+}
+
+// WebhookSubscription is a webhook registered by an API key holder. The
+// secret is only returned when the subscription is created.
+type WebhookSubscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+
+	owner string
+}
+
+// WebhookEvent is the JSON body delivered to a subscribed URL.
+type WebhookEvent struct {
+	Event     string      `json:"event"`
+	Data      interface{} `json:"data"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// WebhookHandler manages webhook subscriptions scoped to the caller's API key
+// and delivers events to them.
+type WebhookHandler struct {
+	mu            sync.RWMutex
+	subscriptions map[string]*WebhookSubscription
+	counter       int
+	client        *http.Client
+	clock         clock.Clock
+}
+
+// NewWebhookHandler creates a new WebhookHandler. Subscriptions and
+// deliveries are limited to publicly routable addresses, and deliveries do
+// not follow redirects.
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{
+		subscriptions: make(map[string]*WebhookSubscription),
+		client:        netguard.NewClient(10 * time.Second),
+		clock:         clock.New(),
+	}
+}
+
+// UseClock sets the time source used for subscription and event timestamps.
+func (h *WebhookHandler) UseClock(c clock.Clock) {
+	h.clock = c
+}
+
+// HandleWebhooks handles POST /api/v1/webhooks (subscribe) and
+// GET /api/v1/webhooks (list the caller's subscriptions).
+func (h *WebhookHandler) HandleWebhooks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.createWebhook(w, r)
+	case http.MethodGet:
+		h.listWebhooks(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// HandleWebhookByID handles DELETE /api/v1/webhooks/:id.
+func (h *WebhookHandler) HandleWebhookByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	id, err := httpjson.PathParam(r.URL.Path, "/api/v1/webhooks/", "webhook ID")
+	if err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
+		return
+	}
+
+	owner := middleware.APIKeyFromContext(r.Context())
+
+	h.mu.Lock()
+	sub, exists := h.subscriptions[id]
+	// Other callers' subscriptions are reported as not found.
+	exists = exists && sub.owner == owner
+	if exists {
+		delete(h.subscriptions, id)
+	}
+	h.mu.Unlock()
+
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("webhook %s not found", id))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Notify delivers event to every subscription of owner that includes it.
+// Deliveries are made asynchronously and failures are logged.
+func (h *WebhookHandler) Notify(owner, event string, data interface{}) {
+	if owner == "" {
+		return
+	}
+
+	h.mu.RLock()
+	var targets []WebhookSubscription
+	for _, sub := range h.subscriptions {
+		if sub.owner == owner && subscribedTo(sub.Events, event) {
+			targets = append(targets, *sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(targets) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(WebhookEvent{Event: event, Data: data, Timestamp: h.clock.Now().UTC()})
+	if err != nil {
+		log.Printf("Webhooks: failed to encode %s event: %v", event, err)
+		return
+	}
+
+	for _, sub := range targets {
+		go h.deliver(sub, event, body)
+	}
+}
+
+func (h *WebhookHandler) deliver(sub WebhookSubscription, event string, body []byte) {
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Webhooks: invalid request for %s: %v", sub.ID, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+signPayload(sub.Secret, body))
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		log.Printf("Webhooks: delivery of %s to %s failed: %v", event, sub.ID, err)
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("Webhooks: delivery of %s to %s returned %d", event, sub.ID, resp.StatusCode)
+	}
+}
+
+func (h *WebhookHandler) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var req CreateWebhookRequest
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
+		return
+	}
+
+	if err := netguard.CheckURL(r.Context(), req.URL); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Events) == 0 {
+		writeError(w, http.StatusBadRequest, "events is required")
+		return
+	}
+	for _, e := range req.Events {
+		if !knownEvents[e] {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported event: %s", e))
+			return
+		}
+	}
+
+	if req.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to generate webhook secret")
+			return
+		}
+		req.Secret = hex.EncodeToString(secret)
+	}
+
+	h.mu.Lock()
+	h.counter++
+	sub := &WebhookSubscription{
+		ID:        fmt.Sprintf("wh-%06d", h.counter),
+		URL:       req.URL,
+		Secret:    req.Secret,
+		Events:    req.Events,
+		CreatedAt: h.clock.Now().UTC(),
+		owner:     middleware.APIKeyFromContext(r.Context()),
+	}
+	h.subscriptions[sub.ID] = sub
+	h.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, sub)
+}
+
+func (h *WebhookHandler) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	owner := middleware.APIKeyFromContext(r.Context())
+
+	h.mu.RLock()
+	subs := make([]WebhookSubscription, 0)
+	for _, sub := range h.subscriptions {
+		if sub.owner == owner {
+			s := *sub
+			s.Secret = ""
+			subs = append(subs, s)
+		}
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"webhooks": subs,
+		"count":    len(subs),
+	})
+}
+
+func subscribedTo(events []string, event string) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
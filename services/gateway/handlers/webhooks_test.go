@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+)
+
+const publicHookURL = "https://203.0.113.10/veritas"
+
+// createHook subscribes url as apiKey and returns the response recorder.
+func createHook(t *testing.T, h *WebhookHandler, apiKey, url string) *httptest.ResponseRecorder {
+	t.Helper()
+	body := `{"url":"` + url + `","events":["` + EventCredentialIssued + `"]}`
+	return serveAs(t, h.HandleWebhooks, apiKey, http.MethodPost, "/api/v1/webhooks", body)
+}
+
+// listHooks returns the IDs of apiKey's subscriptions, failing if any secret
+// is exposed.
+func listHooks(t *testing.T, h *WebhookHandler, apiKey string) []string {
+	t.Helper()
+	rec := serveAs(t, h.HandleWebhooks, apiKey, http.MethodGet, "/api/v1/webhooks", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Webhooks []WebhookSubscription `json:"webhooks"`
+		Count    int                   `json:"count"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, 0, len(resp.Webhooks))
+	for _, sub := range resp.Webhooks {
+		if sub.Secret != "" {
+			t.Errorf("list exposes the secret of %s", sub.ID)
+		}
+		ids = append(ids, sub.ID)
+	}
+	if resp.Count != len(ids) {
+		t.Errorf("count = %d, want %d", resp.Count, len(ids))
+	}
+	return ids
+}
+
+func TestWebhookSubscriptionLifecycle(t *testing.T) {
+	h := NewWebhookHandler()
+
+	rec := createHook(t, h, "key-alice", publicHookURL)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", rec.Code, rec.Body)
+	}
+	var created WebhookSubscription
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Secret == "" {
+		t.Error("create did not return a generated secret")
+	}
+
+	if got := listHooks(t, h, "key-alice"); len(got) != 1 || got[0] != created.ID {
+		t.Errorf("alice's webhooks = %v, want [%s]", got, created.ID)
+	}
+	if got := listHooks(t, h, "key-bob"); len(got) != 0 {
+		t.Errorf("bob sees alice's webhooks: %v", got)
+	}
+
+	path := "/api/v1/webhooks/" + created.ID
+	if rec := serveAs(t, h.HandleWebhookByID, "key-bob", http.MethodDelete, path, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("bob's delete status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := serveAs(t, h.HandleWebhookByID, "key-alice", http.MethodDelete, path, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("alice's delete status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec := serveAs(t, h.HandleWebhookByID, "key-alice", http.MethodDelete, path, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("second delete status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if got := listHooks(t, h, "key-alice"); len(got) != 0 {
+		t.Errorf("webhooks after delete = %v", got)
+	}
+}
+
+func TestCreateWebhookRejectsPrivateTargets(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+	}{
+		{name: "public address", url: publicHookURL, wantStatus: http.StatusCreated},
+		{name: "loopback", url: "http://127.0.0.1:8081/api/v1/credentials/issue", wantStatus: http.StatusBadRequest},
+		{name: "localhost", url: "http://localhost:8082/", wantStatus: http.StatusBadRequest},
+		{name: "RFC 1918", url: "http://10.0.0.5/hook", wantStatus: http.StatusBadRequest},
+		{name: "cloud metadata", url: "http://169.254.169.254/latest/meta-data/", wantStatus: http.StatusBadRequest},
+		{name: "IPv6 loopback", url: "http://[::1]/hook", wantStatus: http.StatusBadRequest},
+		{name: "not http", url: "file:///etc/passwd", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := createHook(t, NewWebhookHandler(), "key-alice", tt.url)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+}
+
+func TestWebhookDeliveryRefusesPrivateAddress(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer srv.Close()
+
+	// A host name can pass the subscribe-time check and later resolve to a
+	// private address; the dial-time check must still refuse it.
+	h := NewWebhookHandler()
+	h.deliver(WebhookSubscription{ID: "wh-000001", URL: srv.URL, Secret: "s"}, EventCredentialIssued, []byte(`{}`))
+
+	if n := atomic.LoadInt32(&hits); n != 0 {
+		t.Errorf("loopback server received %d deliveries", n)
+	}
+}
+
+func TestUseClockStampsGatewayRecords(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	fc := clock.NewFakeClock(now)
+
+	gw := NewGatewayHandler()
+	gw.UseClock(fc)
+	rec := serveAs(t, gw.HandleIssueCredential, "key-alice", http.MethodPost, "/api/v1/credentials/issue",
+		`{"subject_did":"did:veritas:holder","credential_type":["KYCCredential"]}`)
+	var cred IssueCredentialResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &cred); err != nil {
+		t.Fatal(err)
+	}
+	if !cred.CreatedAt.Equal(now) {
+		t.Errorf("credential created_at = %v, want %v", cred.CreatedAt, now)
+	}
+
+	hooks := NewWebhookHandler()
+	hooks.UseClock(fc)
+	var sub WebhookSubscription
+	if err := json.Unmarshal(createHook(t, hooks, "key-alice", publicHookURL).Body.Bytes(), &sub); err != nil {
+		t.Fatal(err)
+	}
+	if !sub.CreatedAt.Equal(now) {
+		t.Errorf("webhook created_at = %v, want %v", sub.CreatedAt, now)
+	}
+}
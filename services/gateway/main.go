@@ -3,16 +3,25 @@ package main
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/veritas-protocol/veritas/services/gateway/handlers"
 	"github.com/veritas-protocol/veritas/services/gateway/middleware"
+	"github.com/veritas-protocol/veritas/services/pkg/config"
+	"github.com/veritas-protocol/veritas/services/pkg/didkey"
+	"github.com/veritas-protocol/veritas/services/pkg/httpserver"
+	"github.com/veritas-protocol/veritas/services/pkg/lifecycle"
+	"github.com/veritas-protocol/veritas/services/pkg/selftest"
 )
 
-const defaultPort = 8081
+const (
+	defaultPort        = 8081
+	defaultIssuerURL   = "http://localhost:8082"
+	defaultVerifierURL = "http://localhost:8083"
+	defaultRegistryURL = "http://localhost:8084"
+)
 
 func main() {
 	port := defaultPort
@@ -20,16 +29,54 @@ func main() {
 		fmt.Sscanf(p, "%d", &port)
 	}
 
-	gatewayHandler := handlers.NewGatewayHandler()
+	var gatewayHandler *handlers.GatewayHandler
+	if keyFile := os.Getenv("VERITAS_SIGNING_KEY_FILE"); keyFile != "" {
+		key, err := didkey.LoadPrivateKey(keyFile)
+		if err != nil {
+			log.Fatalf("Invalid VERITAS_SIGNING_KEY_FILE: %v", err)
+		}
+		gatewayHandler = handlers.NewGatewayHandlerWithKey(key)
+	} else {
+		log.Printf("VERITAS_SIGNING_KEY_FILE not set; signing attestations with an ephemeral key")
+		gatewayHandler = handlers.NewGatewayHandler()
+	}
+	webhookHandler := handlers.NewWebhookHandler()
+	gatewayHandler.UseNotifier(webhookHandler)
+	issuerURL := defaultIssuerURL
+	if u := os.Getenv("VERITAS_ISSUER_URL"); u != "" {
+		issuerURL = u
+	}
+	verifierURL := defaultVerifierURL
+	if u := os.Getenv("VERITAS_VERIFIER_URL"); u != "" {
+		verifierURL = u
+	}
+	registryURL := defaultRegistryURL
+	if u := os.Getenv("VERITAS_REGISTRY_URL"); u != "" {
+		registryURL = u
+	}
+	gatewayHandler.UseResolver(handlers.NewRegistryClient(registryURL))
+	capabilitiesHandler := handlers.NewCapabilitiesHandler(
+		handlers.NewVerifierClient(verifierURL),
+		handlers.NewIssuerClient(issuerURL),
+	)
 	auth := middleware.NewAuthMiddleware()
+	idempotency := middleware.NewIdempotencyMiddleware(middleware.DefaultIdempotencyTTL)
 
 	mux := http.NewServeMux()
 
 	// Protected endpoints (require API key).
-	mux.Handle("/api/v1/credentials/issue", auth.AuthenticateFunc(gatewayHandler.HandleIssueCredential))
-	mux.Handle("/api/v1/credentials/verify", auth.AuthenticateFunc(gatewayHandler.HandleVerifyCredential))
-	mux.Handle("/api/v1/proofs/generate", auth.AuthenticateFunc(gatewayHandler.HandleGenerateProof))
+	mux.Handle("/api/v1/credentials/issue", auth.Authenticate(idempotency.WrapFunc(gatewayHandler.HandleIssueCredential)))
+	mux.Handle("/api/v1/credentials/verify", auth.Authenticate(idempotency.WrapFunc(gatewayHandler.HandleVerifyCredential)))
+	mux.Handle("/api/v1/proofs/generate", auth.Authenticate(idempotency.WrapFunc(gatewayHandler.HandleGenerateProof)))
 	mux.Handle("/api/v1/identity/", auth.AuthenticateFunc(gatewayHandler.HandleResolve))
+	mux.Handle("/api/v1/webhooks", auth.AuthenticateFunc(webhookHandler.HandleWebhooks))
+	mux.Handle("/api/v1/webhooks/", auth.AuthenticateFunc(webhookHandler.HandleWebhookByID))
+
+	// Public capability discovery for wallet onboarding (no auth required).
+	mux.HandleFunc("/api/v1/capabilities", capabilitiesHandler.HandleCapabilities)
+
+	// Public key discovery for verifiers checking kyc_level proofs.
+	mux.HandleFunc("/api/v1/did-document", gatewayHandler.HandleDIDDocument)
 
 	// Health check endpoint (no auth required).
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -38,32 +85,60 @@ func main() {
 		w.Write([]byte(`{"status":"healthy","service":"gateway"}`))
 	})
 
+	cfg := config.LoadFromEnv("gateway")
+	cfg.Port = port
+
+	if selftest.Requested(os.Args[1:]) {
+		os.Exit(selftest.Main(os.Stdout, "gateway",
+			selftest.ConfigCheck(cfg),
+			selftest.HandlerCheck("health", mux, "/health"),
+			selftest.UpstreamCheck("issuer", issuerURL),
+			selftest.UpstreamCheck("verifier", verifierURL),
+			selftest.UpstreamCheck("registry", registryURL),
+		))
+	}
+
 	addr := fmt.Sprintf(":%d", port)
-	server := &http.Server{
-		Addr:    addr,
-		Handler: mux,
+	server, err := httpserver.New(addr, mux, cfg)
+	if err != nil {
+		log.Fatalf("Invalid server configuration: %v", err)
+	}
+
+	lc := lifecycle.New(slog.Default())
+	lc.Register("http-server", server.Start, server.Shutdown)
+	if err := lc.Start(); err != nil {
+		log.Fatalf("Startup failed: %v", err)
 	}
 
+	scheme := "http"
+	if server.TLS() {
+		scheme = "https"
+	}
+	log.Printf("Veritas Gateway starting on %s (%s)", addr, scheme)
+	log.Printf("Endpoints:")
+	log.Printf("  POST /api/v1/credentials/issue   (requires API key)")
+	log.Printf("  POST /api/v1/credentials/verify  (requires API key)")
+	log.Printf("  POST /api/v1/proofs/generate     (requires API key)")
+	log.Printf("  GET  /api/v1/identity/:did       (requires API key)")
+	log.Printf("  POST /api/v1/webhooks            (requires API key)")
+	log.Printf("  GET  /api/v1/webhooks            (requires API key)")
+	log.Printf("  DEL  /api/v1/webhooks/:id        (requires API key)")
+	log.Printf("  GET  /api/v1/capabilities")
+	log.Printf("  GET  /api/v1/did-document")
+	log.Printf("  GET  /health")
+	log.Printf("Auth: X-API-Key header or Authorization: Bearer <key>")
+	log.Printf("POST endpoints honor an optional Idempotency-Key header")
+
 	go func() {
-		log.Printf("Veritas Gateway starting on %s", addr)
-		log.Printf("Endpoints:")
-		log.Printf("  POST /api/v1/credentials/issue   (requires API key)")
-		log.Printf("  POST /api/v1/credentials/verify  (requires API key)")
-		log.Printf("  POST /api/v1/proofs/generate     (requires API key)")
-		log.Printf("  GET  /api/v1/identity/:did       (requires API key)")
-		log.Printf("  GET  /health")
-		log.Printf("Auth: X-API-Key header or Authorization: Bearer <key>")
-
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := <-server.Err(); err != nil {
 			log.Fatalf("Server failed: %v", err)
 		}
 	}()
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
-	sig := <-sigCh
+	sig := lifecycle.WaitForSignal()
 	log.Printf("Received signal %v, shutting down...", sig)
-	server.Close()
+	if err := lc.Shutdown(lifecycle.DefaultShutdownTimeout); err != nil {
+		log.Printf("Shutdown incomplete: %v", err)
+	}
 	log.Println("Veritas Gateway stopped")
 }
@@ -2,6 +2,7 @@
 package middleware
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"strings"
@@ -18,6 +19,10 @@ const (
 	stubAPIKey = "veritas-dev-api-key-placeholder"
 )
 
+// apiKeyContextKey is the request context key under which Authenticate stores
+// the caller's API key.
+type apiKeyContextKey struct{}
+
 // AuthMiddleware provides API key authentication for protected endpoints.
 type AuthMiddleware struct {
 	// validKeys holds the set of valid API keys.
@@ -50,15 +55,7 @@ func NewAuthMiddlewareWithKeys(keys []string) *AuthMiddleware {
 // in the Authorization header.
 func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		apiKey := r.Header.Get(APIKeyHeader)
-
-		// Also check Authorization header for Bearer token.
-		if apiKey == "" {
-			authHeader := r.Header.Get("Authorization")
-			if strings.HasPrefix(authHeader, BearerPrefix) {
-				apiKey = strings.TrimPrefix(authHeader, BearerPrefix)
-			}
-		}
+		apiKey := APIKeyFromRequest(r)
 
 		if apiKey == "" {
 			log.Printf("Auth: request rejected - no API key provided from %s", r.RemoteAddr)
@@ -73,7 +70,8 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 		}
 
 		// API key is valid, proceed to the next handler.
-		next.ServeHTTP(w, r)
+		ctx := context.WithValue(r.Context(), apiKeyContextKey{}, apiKey)
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
@@ -81,3 +79,24 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 func (m *AuthMiddleware) AuthenticateFunc(next http.HandlerFunc) http.Handler {
 	return m.Authenticate(http.HandlerFunc(next))
 }
+
+// APIKeyFromRequest returns the API key supplied in the X-API-Key header or,
+// failing that, as a Bearer token in the Authorization header.
+func APIKeyFromRequest(r *http.Request) string {
+	if apiKey := r.Header.Get(APIKeyHeader); apiKey != "" {
+		return apiKey
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if strings.HasPrefix(authHeader, BearerPrefix) {
+		return strings.TrimPrefix(authHeader, BearerPrefix)
+	}
+	return ""
+}
+
+// APIKeyFromContext returns the API key of the caller authenticated by
+// Authenticate, or "" if the request was not authenticated.
+func APIKeyFromContext(ctx context.Context) string {
+	apiKey, _ := ctx.Value(apiKeyContextKey{}).(string)
+	return apiKey
+}
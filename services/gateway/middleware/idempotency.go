@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+)
+
+const (
+	// IdempotencyKeyHeader is the HTTP header carrying a client-chosen
+	// idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayHeader is set on responses replayed from a previous
+	// request with the same idempotency key.
+	IdempotentReplayHeader = "Idempotent-Replayed"
+
+	// DefaultIdempotencyTTL is how long a recorded response is replayed.
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	// idempotencySweepInterval bounds how often expired entries are evicted.
+	idempotencySweepInterval = time.Minute
+)
+
+// IdempotencyMiddleware records the response to the first request carrying a
+// given Idempotency-Key and replays it for later requests with the same key.
+// Keys are scoped to the caller's API key, method and path.
+type IdempotencyMiddleware struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]*idempotencyEntry
+	lastSweep time.Time
+	clock     clock.Clock
+}
+
+// idempotencyEntry holds a recorded response. done is closed once the first
+// request has finished, so concurrent duplicates wait instead of executing;
+// waiters counts the duplicates that found it still in flight. failed is set if the first request panicked; its entry is then removed so
+// the next attempt runs the handler again.
+type idempotencyEntry struct {
+	done      chan struct{}
+	waiters   int
+	failed    bool
+	status    int
+	header    http.Header
+	body      []byte
+	expiresAt time.Time
+}
+
+// NewIdempotencyMiddleware creates an IdempotencyMiddleware that replays
+// recorded responses for the given TTL.
+func NewIdempotencyMiddleware(ttl time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		ttl:     ttl,
+		entries: make(map[string]*idempotencyEntry),
+		clock:   clock.New(),
+	}
+}
+
+// UseClock sets the time source used to expire recorded responses.
+func (m *IdempotencyMiddleware) UseClock(c clock.Clock) {
+	m.clock = c
+}
+
+// Wrap wraps an http.Handler with idempotency key handling. Requests without
+// an Idempotency-Key header pass through unchanged.
+func (m *IdempotencyMiddleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key = APIKeyFromRequest(r) + "|" + r.Method + "|" + r.URL.Path + "|" + key
+
+		m.mu.Lock()
+		now := m.clock.Now()
+		m.sweepLocked(now)
+		entry, exists := m.entries[key]
+		if exists && entry.expired(now) {
+			delete(m.entries, key)
+			exists = false
+		}
+		if !exists {
+			entry = &idempotencyEntry{done: make(chan struct{})}
+			m.entries[key] = entry
+		} else if entry.expiresAt.IsZero() {
+			entry.waiters++
+		}
+		m.mu.Unlock()
+
+		if exists {
+			select {
+			case <-entry.done:
+			case <-r.Context().Done():
+				return
+			}
+
+			m.mu.Lock()
+			failed := entry.failed
+			m.mu.Unlock()
+			if failed {
+				http.Error(w, `{"error":"Internal Server Error","code":500,"message":"the original request with this Idempotency-Key failed; retry it"}`, http.StatusInternalServerError)
+				return
+			}
+			replay(w, entry)
+			return
+		}
+
+		m.serveFirst(w, r, key, entry, next)
+	})
+}
+
+// serveFirst runs next for the first request with a key and records its
+// response. If next panics, the entry is dropped and waiting duplicates are
+// released with a 500 before the panic propagates.
+func (m *IdempotencyMiddleware) serveFirst(w http.ResponseWriter, r *http.Request, key string, entry *idempotencyEntry, next http.Handler) {
+	completed := false
+	defer func() {
+		if !completed {
+			m.mu.Lock()
+			entry.failed = true
+			delete(m.entries, key)
+			m.mu.Unlock()
+		}
+		close(entry.done)
+	}()
+
+	rec := &responseRecorder{header: make(http.Header), status: http.StatusOK}
+	next.ServeHTTP(rec, r)
+
+	m.mu.Lock()
+	entry.status = rec.status
+	entry.header = rec.header
+	entry.body = rec.body.Bytes()
+	entry.expiresAt = m.clock.Now().Add(m.ttl)
+	m.mu.Unlock()
+	completed = true
+
+	writeRecorded(w, entry)
+}
+
+// sweepLocked evicts expired entries, at most once per sweep interval.
+// m.mu must be held.
+func (m *IdempotencyMiddleware) sweepLocked(now time.Time) {
+	if now.Sub(m.lastSweep) < idempotencySweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, entry := range m.entries {
+		if entry.expired(now) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// expired reports whether a completed entry has outlived its TTL. A zero
+// expiresAt means the first request is still in flight. The caller must hold
+// the middleware's mutex.
+func (e *idempotencyEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// WrapFunc wraps an http.HandlerFunc with idempotency key handling.
+func (m *IdempotencyMiddleware) WrapFunc(next http.HandlerFunc) http.Handler {
+	return m.Wrap(http.HandlerFunc(next))
+}
+
+func replay(w http.ResponseWriter, entry *idempotencyEntry) {
+	w.Header().Set(IdempotentReplayHeader, "true")
+	writeRecorded(w, entry)
+}
+
+func writeRecorded(w http.ResponseWriter, entry *idempotencyEntry) {
+	for k, v := range entry.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(entry.status)
+	w.Write(entry.body)
+}
+
+// responseRecorder buffers a handler's response so it can be stored and
+// written to the client afterwards.
+type responseRecorder struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.status = status
+	r.wroteHeader = true
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(b)
+}
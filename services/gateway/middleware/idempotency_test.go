@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+)
+
+// countingHandler responds 201 with a body numbering each call.
+func countingHandler(calls *int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"call":%d}`, n)
+	})
+}
+
+func idempotentRequest(method, path, apiKey, key string) *http.Request {
+	r := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+	if apiKey != "" {
+		r.Header.Set(APIKeyHeader, apiKey)
+	}
+	if key != "" {
+		r.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return r
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	var calls int32
+	handler := NewIdempotencyMiddleware(time.Hour).Wrap(countingHandler(&calls))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest(http.MethodPost, "/api/v1/credentials/issue", "key-a", "k1"))
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantReplay bool
+	}{
+		{name: "same key replays", req: idempotentRequest(http.MethodPost, "/api/v1/credentials/issue", "key-a", "k1"), wantReplay: true},
+		{name: "other API key runs", req: idempotentRequest(http.MethodPost, "/api/v1/credentials/issue", "key-b", "k1")},
+		{name: "other path runs", req: idempotentRequest(http.MethodPost, "/api/v1/proofs/generate", "key-a", "k1")},
+		{name: "other idempotency key runs", req: idempotentRequest(http.MethodPost, "/api/v1/credentials/issue", "key-a", "k2")},
+		{name: "no idempotency key runs", req: idempotentRequest(http.MethodPost, "/api/v1/credentials/issue", "key-a", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := atomic.LoadInt32(&calls)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.req)
+
+			ran := atomic.LoadInt32(&calls) != before
+			if ran == tt.wantReplay {
+				t.Fatalf("handler ran = %v, want %v", ran, !tt.wantReplay)
+			}
+			if got := rec.Header().Get(IdempotentReplayHeader) == "true"; got != tt.wantReplay {
+				t.Errorf("%s set = %v, want %v", IdempotentReplayHeader, got, tt.wantReplay)
+			}
+			if tt.wantReplay {
+				if rec.Code != first.Code || rec.Body.String() != first.Body.String() {
+					t.Errorf("replay = %d %s, want %d %s", rec.Code, rec.Body, first.Code, first.Body)
+				}
+				if rec.Header().Get("Content-Type") != "application/json" {
+					t.Errorf("replayed Content-Type = %q", rec.Header().Get("Content-Type"))
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyConcurrentDuplicatesRunOnce(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	inner := countingHandler(&calls)
+	m := NewIdempotencyMiddleware(time.Hour)
+	handler := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		inner.ServeHTTP(w, r)
+	}))
+
+	const n = 2
+	recs := make([]*httptest.ResponseRecorder, n)
+	var wg sync.WaitGroup
+	for i := range recs {
+		recs[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(rec *httptest.ResponseRecorder) {
+			defer wg.Done()
+			handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/api/v1/credentials/issue", "key-a", "same"))
+		}(recs[i])
+	}
+
+	// Release the first request only once the second is waiting on it, so
+	// the second cannot take the plain replay path.
+	waitForWaiters(t, m, n-1)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+	for i, rec := range recs {
+		if rec.Code != http.StatusCreated || rec.Body.String() != `{"call":1}` {
+			t.Errorf("response %d = %d %s, want 201 {\"call\":1}", i, rec.Code, rec.Body)
+		}
+	}
+}
+
+// waitForWaiters blocks until n duplicates are waiting on in-flight requests.
+func waitForWaiters(t *testing.T, m *IdempotencyMiddleware, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		m.mu.Lock()
+		waiters := 0
+		for _, entry := range m.entries {
+			waiters += entry.waiters
+		}
+		m.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d requests waiting, want %d", waiters, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestIdempotencyPanicReleasesKey(t *testing.T) {
+	var calls int32
+	handler := NewIdempotencyMiddleware(time.Hour).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic was swallowed, want it propagated")
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/p", "key-a", "k"))
+	}()
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/p", "key-a", "k"))
+		done <- rec
+	}()
+
+	select {
+	case rec := <-done:
+		if rec.Code != http.StatusCreated {
+			t.Errorf("retry status = %d, want %d", rec.Code, http.StatusCreated)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("retry after a panic blocked")
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+}
+
+func TestIdempotencyWaiterHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := NewIdempotencyMiddleware(time.Hour).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	defer close(release)
+
+	go handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/p", "key-a", "k"))
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/p", "key-a", "k").WithContext(ctx))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("waiting duplicate ignored its cancelled context")
+	}
+}
+
+func TestIdempotencyExpiryAndEviction(t *testing.T) {
+	fc := clock.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	var calls int32
+	m := NewIdempotencyMiddleware(time.Hour)
+	m.UseClock(fc)
+	handler := m.Wrap(countingHandler(&calls))
+
+	for i := 0; i < 3; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/p", "key-a", fmt.Sprintf("k%d", i)))
+	}
+
+	fc.Advance(59 * time.Minute)
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/p", "key-a", "k0"))
+	if calls != 3 {
+		t.Fatalf("handler ran %d times within the TTL, want 3", calls)
+	}
+
+	fc.Advance(2 * time.Minute)
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/p", "key-a", "k0"))
+	if calls != 4 {
+		t.Fatalf("handler ran %d times after the TTL, want 4", calls)
+	}
+
+	m.mu.Lock()
+	remaining := len(m.entries)
+	m.mu.Unlock()
+	if remaining != 1 {
+		t.Errorf("%d entries retained after expiry, want 1", remaining)
+	}
+}
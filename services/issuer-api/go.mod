@@ -1,3 +1,7 @@
 module github.com/veritas-protocol/veritas/services/issuer-api
 
 go 1.21
+
+require github.com/veritas-protocol/veritas/services/pkg v0.0.0
+
+replace github.com/veritas-protocol/veritas/services/pkg => ../pkg
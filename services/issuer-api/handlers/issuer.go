@@ -2,11 +2,14 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+	"github.com/veritas-protocol/veritas/services/pkg/httpjson"
 )
 
 // IssueRequest represents a request to issue a verifiable credential.
@@ -43,20 +46,36 @@ type CredentialRecord struct {
 	RevokedAt      *time.Time             `json:"revoked_at,omitempty"`
 }
 
+// StatusList lists the IDs of every revoked credential. UpdatedAt changes
+// whenever a credential is revoked, so verifiers can tell a newer list from
+// an older one.
+type StatusList struct {
+	Revoked   []string  `json:"revoked"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 // IssuerHandler handles issuer API endpoints.
 type IssuerHandler struct {
-	mu          sync.RWMutex
-	credentials map[string]*CredentialRecord
-	counter     int
+	mu              sync.RWMutex
+	credentials     map[string]*CredentialRecord
+	counter         int
+	statusUpdatedAt time.Time
+	clock           clock.Clock
 }
 
 // NewIssuerHandler creates a new IssuerHandler.
 func NewIssuerHandler() *IssuerHandler {
 	return &IssuerHandler{
 		credentials: make(map[string]*CredentialRecord),
+		clock:       clock.New(),
 	}
 }
 
+// UseClock sets the time source used for issuance and revocation timestamps.
+func (h *IssuerHandler) UseClock(c clock.Clock) {
+	h.clock = c
+}
+
 // HandleIssue handles POST /api/v1/issue.
 func (h *IssuerHandler) HandleIssue(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -65,8 +84,8 @@ func (h *IssuerHandler) HandleIssue(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req IssueRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 
@@ -79,7 +98,7 @@ func (h *IssuerHandler) HandleIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	now := time.Now().UTC()
+	now := h.clock.Now().UTC()
 
 	h.mu.Lock()
 	h.counter++
@@ -114,8 +133,8 @@ func (h *IssuerHandler) HandleRevoke(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req RevokeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 
@@ -127,9 +146,10 @@ func (h *IssuerHandler) HandleRevoke(w http.ResponseWriter, r *http.Request) {
 	h.mu.Lock()
 	cred, exists := h.credentials[req.CredentialID]
 	if exists {
-		now := time.Now().UTC()
+		now := h.clock.Now().UTC()
 		cred.Status = "REVOKED"
 		cred.RevokedAt = &now
+		h.statusUpdatedAt = now
 	}
 	h.mu.Unlock()
 
@@ -151,6 +171,12 @@ func (h *IssuerHandler) HandleListIssued(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	page, err := httpjson.ParsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	h.mu.RLock()
 	creds := make([]CredentialRecord, 0, len(h.credentials))
 	for _, c := range h.credentials {
@@ -158,12 +184,46 @@ func (h *IssuerHandler) HandleListIssued(w http.ResponseWriter, r *http.Request)
 	}
 	h.mu.RUnlock()
 
+	// Map iteration order is random; sort so paging and diffs are stable.
+	sort.Slice(creds, func(i, j int) bool {
+		if !creds[i].IssuedAt.Equal(creds[j].IssuedAt) {
+			return creds[i].IssuedAt.Before(creds[j].IssuedAt)
+		}
+		return creds[i].CredentialID < creds[j].CredentialID
+	})
+
+	start, end := page.Bounds(len(creds))
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"credentials": creds,
+		"credentials": creds[start:end],
 		"count":       len(creds),
+		"page_count":  end - start,
+		"total":       len(creds),
+		"next_cursor": page.NextCursor(len(creds)),
 	})
 }
 
+// HandleStatusList handles GET /api/v1/status-list.
+func (h *IssuerHandler) HandleStatusList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	h.mu.RLock()
+	list := StatusList{Revoked: make([]string, 0), UpdatedAt: h.statusUpdatedAt}
+	for id, c := range h.credentials {
+		if c.Status == "REVOKED" {
+			list.Revoked = append(list.Revoked, id)
+		}
+	}
+	h.mu.RUnlock()
+
+	sort.Strings(list.Revoked)
+
+	writeJSON(w, http.StatusOK, list)
+}
+
 // HandleListSchemas handles GET /api/v1/schemas.
 func (h *IssuerHandler) HandleListSchemas(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -185,9 +245,7 @@ func (h *IssuerHandler) HandleListSchemas(w http.ResponseWriter, r *http.Request
 }
 
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+	httpjson.Write(w, status, data)
 }
 
 func writeError(w http.ResponseWriter, status int, message string) {
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+)
+
+// newTestIssuer returns an IssuerHandler driven by a fake clock.
+func newTestIssuer() (*IssuerHandler, *clock.FakeClock) {
+	fc := clock.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	h := NewIssuerHandler()
+	h.UseClock(fc)
+	return h, fc
+}
+
+func issue(t *testing.T, h *IssuerHandler, subject string) string {
+	t.Helper()
+	body := `{"subject_did":"` + subject + `","credential_type":["KYCCredential"],"claims":{"kyc_level":2}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/issue", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.HandleIssue(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("issue status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp IssueResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.CredentialID
+}
+
+// listIssuedIDs returns the credential IDs on one page of GET /api/v1/issued.
+func listIssuedIDs(t *testing.T, h *IssuerHandler, query string) []string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.HandleListIssued(rec, httptest.NewRequest(http.MethodGet, "/api/v1/issued"+query, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Credentials []CredentialRecord `json:"credentials"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, len(resp.Credentials))
+	for i, c := range resp.Credentials {
+		ids[i] = c.CredentialID
+	}
+	return ids
+}
+
+func TestListIssuedOrderIsStable(t *testing.T) {
+	h, fc := newTestIssuer()
+
+	// Several credentials share each timestamp, so ties are broken by ID.
+	var want []string
+	for i := 0; i < 30; i++ {
+		if i%5 == 0 {
+			fc.Advance(time.Second)
+		}
+		want = append(want, issue(t, h, "did:veritas:holder"))
+	}
+
+	first := listIssuedIDs(t, h, "?page_size=100")
+	if !reflect.DeepEqual(first, want) {
+		t.Fatalf("order = %v, want issuance order %v", first, want)
+	}
+	for i := 0; i < 5; i++ {
+		if got := listIssuedIDs(t, h, "?page_size=100"); !reflect.DeepEqual(got, first) {
+			t.Fatalf("call %d order = %v, want %v", i+2, got, first)
+		}
+	}
+
+	var paged []string
+	for page := 1; page <= 3; page++ {
+		paged = append(paged, listIssuedIDs(t, h, "?page_size=10&page="+strconv.Itoa(page))...)
+	}
+	if !reflect.DeepEqual(paged, want) {
+		t.Errorf("pages concatenate to %v, want %v", paged, want)
+	}
+}
+
+func TestUseClockStampsLifecycle(t *testing.T) {
+	h, fc := newTestIssuer()
+	issuedAt := fc.Now()
+	id := issue(t, h, "did:veritas:holder")
+
+	fc.Advance(time.Hour)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/revoke", strings.NewReader(`{"credential_id":"`+id+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.HandleRevoke(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("revoke status = %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.HandleListIssued(rec, httptest.NewRequest(http.MethodGet, "/api/v1/issued", nil))
+	var issued struct {
+		Credentials []CredentialRecord `json:"credentials"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &issued); err != nil {
+		t.Fatal(err)
+	}
+	cred := issued.Credentials[0]
+	if !cred.IssuedAt.Equal(issuedAt) {
+		t.Errorf("issued_at = %v, want %v", cred.IssuedAt, issuedAt)
+	}
+	if cred.RevokedAt == nil || !cred.RevokedAt.Equal(issuedAt.Add(time.Hour)) {
+		t.Errorf("revoked_at = %v, want %v", cred.RevokedAt, issuedAt.Add(time.Hour))
+	}
+
+	rec = httptest.NewRecorder()
+	h.HandleStatusList(rec, httptest.NewRequest(http.MethodGet, "/api/v1/status-list", nil))
+	var list StatusList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if !list.UpdatedAt.Equal(issuedAt.Add(time.Hour)) {
+		t.Errorf("status list updated_at = %v, want %v", list.UpdatedAt, issuedAt.Add(time.Hour))
+	}
+}
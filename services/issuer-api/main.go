@@ -3,12 +3,15 @@ package main
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/veritas-protocol/veritas/services/issuer-api/handlers"
+	"github.com/veritas-protocol/veritas/services/pkg/config"
+	"github.com/veritas-protocol/veritas/services/pkg/httpserver"
+	"github.com/veritas-protocol/veritas/services/pkg/lifecycle"
+	"github.com/veritas-protocol/veritas/services/pkg/selftest"
 )
 
 const defaultPort = 8082
@@ -25,6 +28,7 @@ func main() {
 	mux.HandleFunc("/api/v1/issue", issuerHandler.HandleIssue)
 	mux.HandleFunc("/api/v1/revoke", issuerHandler.HandleRevoke)
 	mux.HandleFunc("/api/v1/issued", issuerHandler.HandleListIssued)
+	mux.HandleFunc("/api/v1/status-list", issuerHandler.HandleStatusList)
 	mux.HandleFunc("/api/v1/schemas", issuerHandler.HandleListSchemas)
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -32,27 +36,53 @@ func main() {
 		w.Write([]byte(`{"status":"healthy","service":"issuer-api"}`))
 	})
 
+	cfg := config.LoadFromEnv("issuer-api")
+	cfg.Port = port
+
+	if selftest.Requested(os.Args[1:]) {
+		os.Exit(selftest.Main(os.Stdout, "issuer-api",
+			selftest.ConfigCheck(cfg),
+			selftest.HandlerCheck("health", mux, "/health"),
+			selftest.HandlerCheck("credential_store", mux, "/api/v1/issued"),
+			selftest.HandlerCheck("schemas", mux, "/api/v1/schemas"),
+		))
+	}
+
 	addr := fmt.Sprintf(":%d", port)
-	server := &http.Server{Addr: addr, Handler: mux}
+	server, err := httpserver.New(addr, mux, cfg)
+	if err != nil {
+		log.Fatalf("Invalid server configuration: %v", err)
+	}
+
+	lc := lifecycle.New(slog.Default())
+	lc.Register("http-server", server.Start, server.Shutdown)
+	if err := lc.Start(); err != nil {
+		log.Fatalf("Startup failed: %v", err)
+	}
+
+	scheme := "http"
+	if server.TLS() {
+		scheme = "https"
+	}
+	log.Printf("Veritas Issuer API starting on %s (%s)", addr, scheme)
+	log.Printf("Endpoints:")
+	log.Printf("  POST /api/v1/issue     — Issue a credential")
+	log.Printf("  POST /api/v1/revoke    — Revoke a credential")
+	log.Printf("  GET  /api/v1/issued    — List issued credentials")
+	log.Printf("  GET  /api/v1/status-list — Revoked credential IDs")
+	log.Printf("  GET  /api/v1/schemas   — List credential schemas")
+	log.Printf("  GET  /health")
 
 	go func() {
-		log.Printf("Veritas Issuer API starting on %s", addr)
-		log.Printf("Endpoints:")
-		log.Printf("  POST /api/v1/issue     — Issue a credential")
-		log.Printf("  POST /api/v1/revoke    — Revoke a credential")
-		log.Printf("  GET  /api/v1/issued    — List issued credentials")
-		log.Printf("  GET  /api/v1/schemas   — List credential schemas")
-		log.Printf("  GET  /health")
-
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := <-server.Err(); err != nil {
 			log.Fatalf("Server failed: %v", err)
 		}
 	}()
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	sig := <-sigCh
+	sig := lifecycle.WaitForSignal()
 	log.Printf("Received signal %v, shutting down...", sig)
-	server.Close()
+	if err := lc.Shutdown(lifecycle.DefaultShutdownTimeout); err != nil {
+		log.Printf("Shutdown incomplete: %v", err)
+	}
 	log.Println("Veritas Issuer API stopped")
 }
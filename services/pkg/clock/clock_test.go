@@ -0,0 +1,81 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestFakeClockNow(t *testing.T) {
+	fc := NewFakeClock(start)
+
+	if got := fc.Now(); !got.Equal(start) {
+		t.Fatalf("Now() = %v, want %v", got, start)
+	}
+
+	fc.Advance(90 * time.Second)
+	if got := fc.Now(); !got.Equal(start.Add(90 * time.Second)) {
+		t.Errorf("Now() after Advance = %v", got)
+	}
+	if got := fc.Since(start); got != 90*time.Second {
+		t.Errorf("Since(start) = %v, want 90s", got)
+	}
+
+	fc.Set(start.Add(-time.Hour))
+	if got := fc.Now(); !got.Equal(start.Add(-time.Hour)) {
+		t.Errorf("Now() after Set = %v", got)
+	}
+}
+
+func TestFakeClockAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		wait     time.Duration
+		move     func(*FakeClock)
+		wantFire bool
+	}{
+		{name: "not yet due", wait: time.Minute, move: func(fc *FakeClock) { fc.Advance(59 * time.Second) }},
+		{name: "due on Advance", wait: time.Minute, move: func(fc *FakeClock) { fc.Advance(time.Minute) }, wantFire: true},
+		{name: "due on Set", wait: time.Minute, move: func(fc *FakeClock) { fc.Set(start.Add(time.Hour)) }, wantFire: true},
+		{name: "Set backwards", wait: time.Minute, move: func(fc *FakeClock) { fc.Set(start.Add(-time.Hour)) }},
+		{name: "zero duration", wait: 0, move: func(fc *FakeClock) {}, wantFire: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc := NewFakeClock(start)
+			ch := fc.After(tt.wait)
+			tt.move(fc)
+
+			select {
+			case got := <-ch:
+				if !tt.wantFire {
+					t.Fatalf("After(%v) fired at %v", tt.wait, got)
+				}
+				if got.Before(start.Add(tt.wait)) {
+					t.Errorf("fired with %v, before its deadline", got)
+				}
+			default:
+				if tt.wantFire {
+					t.Fatalf("After(%v) did not fire", tt.wait)
+				}
+			}
+		})
+	}
+}
+
+func TestFakeClockAfterFiresOnce(t *testing.T) {
+	fc := NewFakeClock(start)
+	ch := fc.After(time.Second)
+
+	fc.Advance(time.Second)
+	fc.Advance(time.Second)
+
+	<-ch
+	select {
+	case <-ch:
+		t.Error("After channel fired twice")
+	default:
+	}
+}
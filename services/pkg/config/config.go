@@ -15,6 +15,24 @@ type AppConfig struct {
 	Port        int
 	LogLevel    string
 	MetricsPort int
+
+	// TLSCertFile and TLSKeyFile enable HTTPS when both are set.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSMinVersion is the minimum accepted TLS version ("1.2" or "1.3").
+	TLSMinVersion string
+	// TLSRedirectPort, when non-zero, serves a plain HTTP listener on this
+	// port that redirects every request to HTTPS.
+	TLSRedirectPort int
+
+	// MaxBodyBytes is the largest JSON request body handlers decode; larger
+	// bodies are rejected with 413.
+	MaxBodyBytes int64
+
+	// DisabledRoutes lists request paths that respond with 503 Service
+	// Unavailable, e.g. during maintenance. A path ending in "/" disables
+	// every path beneath it.
+	DisabledRoutes []string
 }
 
 // DefaultConfig returns an AppConfig with sensible defaults.
@@ -24,7 +42,53 @@ func DefaultConfig(name string) AppConfig {
 		Port:        8080,
 		LogLevel:    "info",
 		MetricsPort: 9090,
+
+		TLSMinVersion: "1.2",
+
+		MaxBodyBytes: 1 << 20,
+	}
+}
+
+// TLSEnabled reports whether both a certificate and key file are configured.
+func (c AppConfig) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// Validate reports the first problem found in the configuration.
+func (c AppConfig) Validate() error {
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("config: port %d out of range", c.Port)
+	}
+	if c.MetricsPort < 0 || c.MetricsPort > 65535 {
+		return fmt.Errorf("config: metrics_port %d out of range", c.MetricsPort)
+	}
+
+	switch strings.ToLower(c.LogLevel) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		return fmt.Errorf("config: unknown log_level %q", c.LogLevel)
 	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("config: tls_cert_file and tls_key_file must be set together")
+	}
+	if c.TLSEnabled() {
+		for _, path := range []string{c.TLSCertFile, c.TLSKeyFile} {
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("config: TLS file %s: %w", path, err)
+			}
+		}
+	}
+	switch c.TLSMinVersion {
+	case "", "1.2", "1.3":
+	default:
+		return fmt.Errorf("config: unsupported tls_min_version %q", c.TLSMinVersion)
+	}
+	if c.MaxBodyBytes < 1 {
+		return fmt.Errorf("config: max_body_bytes must be positive, got %d", c.MaxBodyBytes)
+	}
+
+	return nil
 }
 
 // LoadFromFile loads configuration from a TOML-style file.
@@ -71,6 +135,26 @@ func LoadFromFile(path string) (AppConfig, error) {
 				return cfg, fmt.Errorf("config: invalid metrics_port value %q: %w", value, err)
 			}
 			cfg.MetricsPort = p
+		case "tls_cert_file":
+			cfg.TLSCertFile = value
+		case "tls_key_file":
+			cfg.TLSKeyFile = value
+		case "tls_min_version":
+			cfg.TLSMinVersion = value
+		case "tls_redirect_port":
+			p, err := strconv.Atoi(value)
+			if err != nil {
+				return cfg, fmt.Errorf("config: invalid tls_redirect_port value %q: %w", value, err)
+			}
+			cfg.TLSRedirectPort = p
+		case "max_body_bytes":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return cfg, fmt.Errorf("config: invalid max_body_bytes value %q: %w", value, err)
+			}
+			cfg.MaxBodyBytes = n
+		case "disabled_routes":
+			cfg.DisabledRoutes = splitList(value)
 		}
 	}
 
@@ -102,5 +186,44 @@ func LoadFromEnv(name string) AppConfig {
 		}
 	}
 
+	if v := os.Getenv("VERITAS_TLS_CERT_FILE"); v != "" {
+		cfg.TLSCertFile = v
+	}
+
+	if v := os.Getenv("VERITAS_TLS_KEY_FILE"); v != "" {
+		cfg.TLSKeyFile = v
+	}
+
+	if v := os.Getenv("VERITAS_TLS_MIN_VERSION"); v != "" {
+		cfg.TLSMinVersion = v
+	}
+
+	if v := os.Getenv("VERITAS_TLS_REDIRECT_PORT"); v != "" {
+		if p, err := strconv.Atoi(v); err == nil {
+			cfg.TLSRedirectPort = p
+		}
+	}
+
+	if v := os.Getenv("VERITAS_MAX_BODY_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			cfg.MaxBodyBytes = n
+		}
+	}
+
+	if v := os.Getenv("VERITAS_DISABLED_ROUTES"); v != "" {
+		cfg.DisabledRoutes = splitList(v)
+	}
+
 	return cfg
 }
+
+// splitList parses a comma-separated list, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,153 @@
+// Package didkey loads Ed25519 signing keys and publishes their public halves
+// in DID documents, so other services can check what a Veritas service signs.
+package didkey
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// ed25519MulticodecPrefix is the multicodec header for Ed25519 public keys.
+var ed25519MulticodecPrefix = []byte{0xed, 0x01}
+
+// Document is a DID document publishing a service's signing keys.
+type Document struct {
+	ID                 string               `json:"id"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod"`
+	AssertionMethod    []string             `json:"assertionMethod"`
+}
+
+// VerificationMethod is a public key entry in a DID document.
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase"`
+}
+
+// NewDocument returns the DID document for did, publishing pub as the
+// assertion method keyID.
+func NewDocument(did, keyID string, pub ed25519.PublicKey) Document {
+	return Document{
+		ID: did,
+		VerificationMethod: []VerificationMethod{{
+			ID:                 keyID,
+			Type:               "Ed25519VerificationKey2020",
+			Controller:         did,
+			PublicKeyMultibase: EncodeMultibase(pub),
+		}},
+		AssertionMethod: []string{keyID},
+	}
+}
+
+// AssertionKey returns the Ed25519 key published under keyID, which must be
+// an assertion method of the document.
+func (d Document) AssertionKey(keyID string) (ed25519.PublicKey, error) {
+	asserted := false
+	for _, id := range d.AssertionMethod {
+		if id == keyID {
+			asserted = true
+		}
+	}
+	if !asserted {
+		return nil, fmt.Errorf("%s is not an assertion method of %s", keyID, d.ID)
+	}
+
+	for _, vm := range d.VerificationMethod {
+		if vm.ID == keyID {
+			return DecodeMultibase(vm.PublicKeyMultibase)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in %s", keyID, d.ID)
+}
+
+// LoadPrivateKey reads a PEM-encoded PKCS #8 Ed25519 private key, as written
+// by `openssl genpkey -algorithm ed25519`.
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("%s: no PEM PRIVATE KEY block", path)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an Ed25519 key", path)
+	}
+	return edKey, nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// EncodeMultibase encodes an Ed25519 public key as base58btc multibase
+// ("z...") with the Ed25519 multicodec prefix, as in did:key.
+func EncodeMultibase(pub ed25519.PublicKey) string {
+	data := append(append([]byte{}, ed25519MulticodecPrefix...), pub...)
+
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return "z" + string(out)
+}
+
+// DecodeMultibase reverses EncodeMultibase.
+func DecodeMultibase(s string) (ed25519.PublicKey, error) {
+	if len(s) < 2 || s[0] != 'z' {
+		return nil, errors.New("public key is not base58btc multibase")
+	}
+
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	zeros := 0
+	for i, c := range s[1:] {
+		digit := -1
+		for j, a := range base58Alphabet {
+			if a == c {
+				digit = j
+				break
+			}
+		}
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		if digit == 0 && i == zeros {
+			zeros++
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+	data := append(make([]byte, zeros), n.Bytes()...)
+
+	if len(data) != len(ed25519MulticodecPrefix)+ed25519.PublicKeySize ||
+		data[0] != ed25519MulticodecPrefix[0] || data[1] != ed25519MulticodecPrefix[1] {
+		return nil, errors.New("public key is not a multicodec Ed25519 key")
+	}
+	return ed25519.PublicKey(data[len(ed25519MulticodecPrefix):]), nil
+}
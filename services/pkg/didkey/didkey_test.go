@@ -0,0 +1,132 @@
+package didkey
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv
+}
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrivateKey(t *testing.T) {
+	key := newTestKey(t)
+	edDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "ed25519 PKCS8", path: writePEM(t, "PRIVATE KEY", edDER)},
+		{name: "ECDSA key", path: writePEM(t, "PRIVATE KEY", ecDER), wantErr: true},
+		{name: "wrong block type", path: writePEM(t, "EC PRIVATE KEY", edDER), wantErr: true},
+		{name: "missing file", path: filepath.Join(t.TempDir(), "absent.pem"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadPrivateKey(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LoadPrivateKey succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadPrivateKey: %v", err)
+			}
+			if !got.Equal(key) {
+				t.Error("loaded key differs from the written key")
+			}
+		})
+	}
+}
+
+func TestMultibaseRoundTrip(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		pub := newTestKey(t).Public().(ed25519.PublicKey)
+		encoded := EncodeMultibase(pub)
+		if !strings.HasPrefix(encoded, "z6Mk") {
+			t.Errorf("encoded key %s lacks the did:key Ed25519 prefix z6Mk", encoded)
+		}
+		decoded, err := DecodeMultibase(encoded)
+		if err != nil {
+			t.Fatalf("decode %s: %v", encoded, err)
+		}
+		if !bytes.Equal(decoded, pub) {
+			t.Fatalf("round trip of %x gave %x", pub, decoded)
+		}
+	}
+}
+
+func TestDocumentAssertionKey(t *testing.T) {
+	pub := newTestKey(t).Public().(ed25519.PublicKey)
+	doc := NewDocument("did:veritas:key:test", "did:veritas:key:test#signing-key", pub)
+
+	notAsserted := doc
+	notAsserted.AssertionMethod = nil
+
+	tests := []struct {
+		name    string
+		doc     Document
+		keyID   string
+		wantErr bool
+	}{
+		{name: "published key", doc: doc, keyID: "did:veritas:key:test#signing-key"},
+		{name: "unknown key ID", doc: doc, keyID: "did:veritas:key:test#other", wantErr: true},
+		{name: "not an assertion method", doc: notAsserted, keyID: "did:veritas:key:test#signing-key", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.doc.AssertionKey(tt.keyID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("AssertionKey succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AssertionKey: %v", err)
+			}
+			if !got.Equal(pub) {
+				t.Error("AssertionKey returned a different key")
+			}
+		})
+	}
+}
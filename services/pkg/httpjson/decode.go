@@ -0,0 +1,99 @@
+package httpjson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the largest JSON request body Decode accepts unless
+// the request passed through LimitBody.
+const DefaultMaxBodyBytes = 1 << 20
+
+// bodyLimitContextKey carries the limit set by LimitBody.
+type bodyLimitContextKey struct{}
+
+// LimitBody makes Decode accept request bodies of up to limit bytes for
+// requests served by next. Services set it from config.AppConfig.MaxBodyBytes.
+func LimitBody(next http.Handler, limit int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), bodyLimitContextKey{}, limit)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// bodyLimit returns the body limit that applies to r.
+func bodyLimit(r *http.Request) int64 {
+	if limit, ok := r.Context().Value(bodyLimitContextKey{}).(int64); ok && limit > 0 {
+		return limit
+	}
+	return DefaultMaxBodyBytes
+}
+
+// DecodeError describes why a request body could not be decoded, along with
+// the HTTP status the handler should respond with.
+type DecodeError struct {
+	Status  int
+	Message string
+}
+
+func (e *DecodeError) Error() string {
+	return e.Message
+}
+
+// Decode requires r to carry a JSON Content-Type (application/json or an
+// application/*+json type such as application/ld+json), limits the body to
+// DefaultMaxBodyBytes or the limit set by LimitBody, and decodes it into v. Failures are returned as *DecodeError
+// with status 415, 413 or 400 respectively.
+func Decode(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if !isJSONContentType(r.Header.Get("Content-Type")) {
+		return &DecodeError{
+			Status:  http.StatusUnsupportedMediaType,
+			Message: "Content-Type must be application/json",
+		}
+	}
+
+	limit := bodyLimit(r)
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return &DecodeError{
+				Status:  http.StatusRequestEntityTooLarge,
+				Message: fmt.Sprintf("request body exceeds %d bytes", limit),
+			}
+		}
+		return &DecodeError{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("invalid request body: %v", err),
+		}
+	}
+	return nil
+}
+
+// StatusCode returns the HTTP status for an error returned by Decode or
+// PathParam, defaulting to 400 Bad Request.
+func StatusCode(err error) int {
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		return decodeErr.Status
+	}
+	var pathErr *PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Status
+	}
+	return http.StatusBadRequest
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
@@ -0,0 +1,97 @@
+package httpjson
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	oversized := `{"name":"` + strings.Repeat("a", DefaultMaxBodyBytes) + `"}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantName    string
+	}{
+		{name: "application/json", contentType: "application/json", body: `{"name":"alice"}`, wantName: "alice"},
+		{name: "with charset", contentType: "application/json; charset=utf-8", body: `{"name":"alice"}`, wantName: "alice"},
+		{name: "structured suffix", contentType: "application/ld+json", body: `{"name":"alice"}`, wantName: "alice"},
+		{name: "missing content type", body: `{"name":"alice"}`, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "form encoded", contentType: "application/x-www-form-urlencoded", body: `name=alice`, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "plain text", contentType: "text/plain", body: `{"name":"alice"}`, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "oversized body", contentType: "application/json", body: oversized, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "malformed JSON", contentType: "application/json", body: `{"name":`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			var v struct {
+				Name string `json:"name"`
+			}
+			err := Decode(httptest.NewRecorder(), req, &v)
+
+			if tt.wantStatus != 0 {
+				if err == nil {
+					t.Fatal("Decode succeeded, want error")
+				}
+				if got := StatusCode(err); got != tt.wantStatus {
+					t.Errorf("StatusCode = %d, want %d (%v)", got, tt.wantStatus, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if v.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", v.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestLimitBody(t *testing.T) {
+	body := `{"name":"` + strings.Repeat("a", 64) + `"}`
+
+	tests := []struct {
+		name       string
+		limit      int64
+		wantStatus int
+	}{
+		{name: "below the limit", limit: 1024, wantStatus: http.StatusNoContent},
+		{name: "above the limit", limit: 16, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "non-positive limit uses the default", limit: 0, wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := LimitBody(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var v struct {
+					Name string `json:"name"`
+				}
+				if err := Decode(w, r, &v); err != nil {
+					w.WriteHeader(StatusCode(err))
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}), tt.limit)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
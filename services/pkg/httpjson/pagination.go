@@ -0,0 +1,147 @@
+// Package httpjson provides request and response helpers shared by the
+// Veritas JSON HTTP APIs.
+package httpjson
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultPageSize is used when a request does not specify page_size.
+	DefaultPageSize = 50
+
+	// MaxPageSize is the largest page_size honored; larger values are clamped.
+	MaxPageSize = 200
+
+	cursorPrefix = "offset:"
+)
+
+// ErrInvalidPagination is wrapped by all errors returned from
+// ParsePagination, so handlers can map it to 400 Bad Request.
+var ErrInvalidPagination = errors.New("invalid pagination parameters")
+
+// Pagination holds validated paging parameters for a list endpoint.
+type Pagination struct {
+	Page     int
+	PageSize int
+	// Cursor is the opaque cursor from the request, if any. When set it takes
+	// precedence over Page.
+	Cursor string
+
+	offset int
+	// all is set when the request named no paging parameter; the page then
+	// covers every item.
+	all bool
+}
+
+// ParsePagination reads page, page_size and cursor from the query string.
+// Page defaults to 1 and page_size to DefaultPageSize; page_size is clamped
+// to MaxPageSize. A request that names none of them is not paged: Bounds
+// covers every item, so clients written before paging still get the whole
+// list. Non-numeric or non-positive values, pages whose offset
+// would overflow, and malformed cursors produce an error wrapping
+// ErrInvalidPagination.
+func ParsePagination(r *http.Request) (Pagination, error) {
+	q := r.URL.Query()
+	p := Pagination{Page: 1, PageSize: DefaultPageSize}
+	p.all = !q.Has("page") && !q.Has("page_size") && !q.Has("cursor")
+
+	if v := q.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return p, fmt.Errorf("%w: page must be a positive integer, got %q", ErrInvalidPagination, v)
+		}
+		p.Page = n
+	}
+
+	if v := q.Get("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return p, fmt.Errorf("%w: page_size must be a positive integer, got %q", ErrInvalidPagination, v)
+		}
+		if n > MaxPageSize {
+			n = MaxPageSize
+		}
+		p.PageSize = n
+	}
+
+	if p.Page-1 > math.MaxInt/p.PageSize {
+		return p, fmt.Errorf("%w: page %d is out of range", ErrInvalidPagination, p.Page)
+	}
+	p.offset = (p.Page - 1) * p.PageSize
+
+	if v := q.Get("cursor"); v != "" {
+		offset, err := decodeCursor(v)
+		if err != nil {
+			return p, fmt.Errorf("%w: malformed cursor", ErrInvalidPagination)
+		}
+		p.Cursor = v
+		p.offset = offset
+	}
+
+	return p, nil
+}
+
+// Paged reports whether the request asked for a page rather than the whole
+// list.
+func (p Pagination) Paged() bool {
+	return !p.all
+}
+
+// Offset returns the index of the first item on the requested page.
+func (p Pagination) Offset() int {
+	return p.offset
+}
+
+// Bounds returns the slice bounds of the requested page within a list of
+// total items. Both bounds always lie within [0, total]. An unpaged request
+// covers all of them.
+func (p Pagination) Bounds(total int) (start, end int) {
+	if p.all {
+		return 0, total
+	}
+	start = p.offset
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end = start + p.PageSize
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// NextCursor returns the cursor for the page after this one, or "" if this
+// is the last page.
+func (p Pagination) NextCursor(total int) string {
+	_, end := p.Bounds(total)
+	if end >= total {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(end)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	s, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, errors.New("missing cursor prefix")
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, errors.New("invalid cursor offset")
+	}
+	return n, nil
+}
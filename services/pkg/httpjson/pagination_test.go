@@ -0,0 +1,162 @@
+package httpjson
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestParsePagination(t *testing.T) {
+	cursor := func(offset int) string {
+		return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		wantErr      bool
+		wantPage     int
+		wantPageSize int
+		wantOffset   int
+	}{
+		{name: "defaults", query: "", wantPage: 1, wantPageSize: DefaultPageSize, wantOffset: 0},
+		{name: "explicit page", query: "page=3&page_size=10", wantPage: 3, wantPageSize: 10, wantOffset: 20},
+		{name: "page_size clamped", query: "page_size=5000", wantPage: 1, wantPageSize: MaxPageSize, wantOffset: 0},
+		{name: "clamped page_size used for offset", query: "page=2&page_size=5000", wantPage: 2, wantPageSize: MaxPageSize, wantOffset: MaxPageSize},
+		{name: "cursor overrides page", query: "page=9&page_size=10&cursor=" + cursor(30), wantPage: 9, wantPageSize: 10, wantOffset: 30},
+		{name: "largest page that fits", query: "page_size=200&page=" + strconv.Itoa(math.MaxInt/200+1), wantPage: math.MaxInt/200 + 1, wantPageSize: 200, wantOffset: (math.MaxInt / 200) * 200},
+		{name: "page overflows offset", query: "page_size=200&page=" + strconv.Itoa(math.MaxInt), wantErr: true},
+		{name: "page just past overflow", query: "page_size=200&page=" + strconv.Itoa(math.MaxInt/200+2), wantErr: true},
+		{name: "page zero", query: "page=0", wantErr: true},
+		{name: "negative page", query: "page=-1", wantErr: true},
+		{name: "non-numeric page", query: "page=two", wantErr: true},
+		{name: "page beyond int range", query: "page=99999999999999999999", wantErr: true},
+		{name: "page_size zero", query: "page_size=0", wantErr: true},
+		{name: "non-numeric page_size", query: "page_size=lots", wantErr: true},
+		{name: "cursor not base64", query: "cursor=!!!", wantErr: true},
+		{name: "cursor without prefix", query: "cursor=" + base64.RawURLEncoding.EncodeToString([]byte("12")), wantErr: true},
+		{name: "cursor negative offset", query: "cursor=" + cursor(-5), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParsePagination(httptest.NewRequest("GET", "/api/v1/dids?"+tt.query, nil))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPagination) {
+					t.Fatalf("err = %v, want ErrInvalidPagination", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePagination: %v", err)
+			}
+			if p.Page != tt.wantPage || p.PageSize != tt.wantPageSize || p.Offset() != tt.wantOffset {
+				t.Errorf("got page=%d page_size=%d offset=%d, want %d %d %d",
+					p.Page, p.PageSize, p.Offset(), tt.wantPage, tt.wantPageSize, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestPaginationBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		p         Pagination
+		total     int
+		wantStart int
+		wantEnd   int
+		wantNext  bool
+	}{
+		{name: "first page", p: Pagination{PageSize: 10, offset: 0}, total: 25, wantStart: 0, wantEnd: 10, wantNext: true},
+		{name: "last partial page", p: Pagination{PageSize: 10, offset: 20}, total: 25, wantStart: 20, wantEnd: 25},
+		{name: "exact last page", p: Pagination{PageSize: 10, offset: 10}, total: 20, wantStart: 10, wantEnd: 20},
+		{name: "past the end", p: Pagination{PageSize: 10, offset: 1000}, total: 25, wantStart: 25, wantEnd: 25},
+		{name: "huge offset", p: Pagination{PageSize: 200, offset: math.MaxInt}, total: 3, wantStart: 3, wantEnd: 3},
+		{name: "negative offset clamped", p: Pagination{PageSize: 200, offset: -400}, total: 3, wantStart: 0, wantEnd: 3},
+		{name: "empty list", p: Pagination{PageSize: 10}, total: 0, wantStart: 0, wantEnd: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.p.Bounds(tt.total)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("Bounds(%d) = (%d, %d), want (%d, %d)", tt.total, start, end, tt.wantStart, tt.wantEnd)
+			}
+			if got := tt.p.NextCursor(tt.total) != ""; got != tt.wantNext {
+				t.Errorf("NextCursor present = %v, want %v", got, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestPaginationCursorWalk(t *testing.T) {
+	const total = 23
+	seen := make([]bool, total)
+
+	query := "page_size=5"
+	for pages := 0; ; pages++ {
+		if pages > total {
+			t.Fatal("cursor walk did not terminate")
+		}
+		p, err := ParsePagination(httptest.NewRequest("GET", "/api/v1/schemas?"+query, nil))
+		if err != nil {
+			t.Fatalf("ParsePagination(%s): %v", query, err)
+		}
+		start, end := p.Bounds(total)
+		for i := start; i < end; i++ {
+			if seen[i] {
+				t.Fatalf("item %d returned twice", i)
+			}
+			seen[i] = true
+		}
+
+		next := p.NextCursor(total)
+		if next == "" {
+			break
+		}
+		query = "page_size=5&cursor=" + next
+	}
+
+	for i, ok := range seen {
+		if !ok {
+			t.Errorf("item %d never returned", i)
+		}
+	}
+}
+
+func TestParsePaginationUnpaged(t *testing.T) {
+	const total = 3 * MaxPageSize
+
+	tests := []struct {
+		query     string
+		wantPaged bool
+		wantEnd   int
+	}{
+		{query: "", wantEnd: total},
+		{query: "sort=asc", wantEnd: total},
+		{query: "page=1", wantPaged: true, wantEnd: DefaultPageSize},
+		{query: "page_size=10", wantPaged: true, wantEnd: 10},
+		{query: "cursor=" + base64.RawURLEncoding.EncodeToString([]byte("offset:0")), wantPaged: true, wantEnd: DefaultPageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			p, err := ParsePagination(httptest.NewRequest("GET", "/api/v1/dids?"+tt.query, nil))
+			if err != nil {
+				t.Fatalf("ParsePagination: %v", err)
+			}
+			if p.Paged() != tt.wantPaged {
+				t.Errorf("Paged() = %v, want %v", p.Paged(), tt.wantPaged)
+			}
+			if start, end := p.Bounds(total); start != 0 || end != tt.wantEnd {
+				t.Errorf("Bounds(%d) = (%d, %d), want (0, %d)", total, start, end, tt.wantEnd)
+			}
+			if next := p.NextCursor(total); (next != "") != tt.wantPaged {
+				t.Errorf("NextCursor = %q, want present: %v", next, tt.wantPaged)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package httpjson
+
+import (
+	"net/http"
+	"strings"
+)
+
+// PathError describes a malformed request path, along with the HTTP status
+// the handler should respond with.
+type PathError struct {
+	Status  int
+	Message string
+}
+
+func (e *PathError) Error() string {
+	return e.Message
+}
+
+// PathParam returns the single path segment that follows prefix, such as the
+// ID in /api/v1/dids/{id}. One trailing slash after the segment is ignored.
+// An empty segment yields a 400 *PathError naming the parameter, and any
+// further segments yield a 404 *PathError so that mistyped URLs are not
+// silently served by the parent resource.
+func PathParam(path, prefix, name string) (string, error) {
+	rest := strings.TrimSuffix(strings.TrimPrefix(path, prefix), "/")
+
+	if rest == "" {
+		return "", &PathError{Status: http.StatusBadRequest, Message: name + " is required"}
+	}
+	if strings.Contains(rest, "/") {
+		return "", &PathError{Status: http.StatusNotFound, Message: "no such resource: " + path}
+	}
+	return rest, nil
+}
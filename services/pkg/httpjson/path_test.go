@@ -0,0 +1,43 @@
+package httpjson
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPathParam(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		want       string
+		wantStatus int
+	}{
+		{name: "single segment", path: "/api/v1/dids/did:veritas:alice", want: "did:veritas:alice"},
+		{name: "trailing slash", path: "/api/v1/dids/did:veritas:alice/", want: "did:veritas:alice"},
+		{name: "escaped characters kept", path: "/api/v1/dids/did:web:example.com%3A8443", want: "did:web:example.com%3A8443"},
+		{name: "empty ID", path: "/api/v1/dids/", wantStatus: http.StatusBadRequest},
+		{name: "extra segments", path: "/api/v1/dids/did:veritas:alice/keys", wantStatus: http.StatusNotFound},
+		{name: "double trailing slash", path: "/api/v1/dids/did:veritas:alice//", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PathParam(tt.path, "/api/v1/dids/", "DID")
+			if tt.wantStatus != 0 {
+				if err == nil {
+					t.Fatalf("PathParam = %q, want error", got)
+				}
+				if status := StatusCode(err); status != tt.wantStatus {
+					t.Errorf("StatusCode = %d, want %d", status, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PathParam: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PathParam = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package httpjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// EncodeError reports a response value that could not be marshaled to JSON.
+type EncodeError struct {
+	Err error
+}
+
+func (e *EncodeError) Error() string {
+	return "encode response: " + e.Err.Error()
+}
+
+func (e *EncodeError) Unwrap() error {
+	return e.Err
+}
+
+// Write encodes v as JSON and writes it with the given status. The body is
+// encoded before any header is written. If encoding fails, the client gets a
+// 500 JSON error instead of a truncated response under the intended status,
+// and Write returns an *EncodeError.
+func Write(w http.ResponseWriter, status int, v interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		log.Printf("httpjson: failed to encode %d response: %v", status, err)
+		writeBuffered(w, http.StatusInternalServerError, []byte(`{"error":"Internal Server Error","code":500,"message":"failed to encode response"}`+"\n"))
+		return &EncodeError{Err: err}
+	}
+
+	writeBuffered(w, status, buf.Bytes())
+	return nil
+}
+
+func writeBuffered(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
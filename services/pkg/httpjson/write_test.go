@@ -0,0 +1,49 @@
+package httpjson
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		value      interface{}
+		wantStatus int
+		wantErr    bool
+	}{
+		{name: "encodable value", status: http.StatusCreated, value: map[string]string{"id": "vc-1"}, wantStatus: http.StatusCreated},
+		{name: "channel", status: http.StatusOK, value: make(chan int), wantStatus: http.StatusInternalServerError, wantErr: true},
+		{name: "function in a struct", status: http.StatusOK, value: struct{ F func() }{}, wantStatus: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			err := Write(rec, tt.status, tt.value)
+
+			var encErr *EncodeError
+			if tt.wantErr != errors.As(err, &encErr) {
+				t.Fatalf("Write error = %v, want EncodeError: %v", err, tt.wantErr)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q is not JSON: %v", rec.Body, err)
+			}
+			if tt.wantErr && body["code"] != float64(http.StatusInternalServerError) {
+				t.Errorf("body = %v, want a 500 error", body)
+			}
+		})
+	}
+}
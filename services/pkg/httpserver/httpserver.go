@@ -0,0 +1,193 @@
+// Package httpserver provides a shared HTTP server for Veritas services that
+// serves over TLS when certificates are configured and plain HTTP otherwise.
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/veritas-protocol/veritas/services/pkg/config"
+	"github.com/veritas-protocol/veritas/services/pkg/httpjson"
+)
+
+// Server wraps an http.Server with optional TLS and HTTP-to-HTTPS redirect.
+type Server struct {
+	server   *http.Server
+	redirect *http.Server
+	certFile string
+	keyFile  string
+	errc     chan error
+}
+
+// New creates a Server listening on addr. TLS is enabled when cfg has both a
+// certificate and key file; otherwise the server falls back to plain HTTP.
+// Routes listed in cfg.DisabledRoutes respond with 503, and httpjson.Decode
+// accepts bodies of up to cfg.MaxBodyBytes. cfg is validated
+// first, so a half-configured TLS setup fails here instead of silently
+// serving plain HTTP.
+func New(addr string, handler http.Handler, cfg config.AppConfig) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	handler = httpjson.LimitBody(handler, cfg.MaxBodyBytes)
+	if len(cfg.DisabledRoutes) > 0 {
+		handler = DisableRoutes(handler, cfg.DisabledRoutes)
+	}
+
+	s := &Server{
+		server: &http.Server{Addr: addr, Handler: handler},
+		errc:   make(chan error, 1),
+	}
+
+	if !cfg.TLSEnabled() {
+		return s, nil
+	}
+
+	minVersion, err := ParseTLSVersion(cfg.TLSMinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.certFile = cfg.TLSCertFile
+	s.keyFile = cfg.TLSKeyFile
+	s.server.TLSConfig = &tls.Config{MinVersion: minVersion}
+
+	if cfg.TLSRedirectPort != 0 {
+		_, httpsPort, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("httpserver: invalid address %q: %w", addr, err)
+		}
+		s.redirect = &http.Server{
+			Addr:    fmt.Sprintf(":%d", cfg.TLSRedirectPort),
+			Handler: RedirectHandler(httpsPort),
+		}
+	}
+
+	return s, nil
+}
+
+// TLS reports whether the server serves HTTPS.
+func (s *Server) TLS() bool {
+	return s.server.TLSConfig != nil
+}
+
+// Start binds the server's address and serves in the background, so that a
+// port already in use is reported before the service announces itself. It
+// has the signature of a lifecycle.StartFunc. The result of serving is
+// delivered on Err.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	go func() { s.errc <- s.Serve(ln) }()
+	return nil
+}
+
+// Err returns a channel that receives the result of serving after Start:
+// nil once the server has been shut down, or the error that stopped it.
+func (s *Server) Err() <-chan error {
+	return s.errc
+}
+
+// ListenAndServe listens on the server's address and calls Serve.
+func (s *Server) ListenAndServe() error {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve accepts connections on ln and, if configured, starts the redirect
+// listener. It blocks until the main server stops and never returns
+// http.ErrServerClosed. If the redirect listener cannot bind or fails while
+// serving, the main server is closed and the redirect error is returned.
+func (s *Server) Serve(ln net.Listener) error {
+	if !s.TLS() {
+		return ignoreClosed(s.server.Serve(ln))
+	}
+
+	redirectErr := make(chan error, 1)
+	if s.redirect != nil {
+		redirectLn, err := net.Listen("tcp", s.redirect.Addr)
+		if err != nil {
+			ln.Close()
+			return fmt.Errorf("httpserver: redirect listener: %w", err)
+		}
+		go func() {
+			if err := s.redirect.Serve(redirectLn); !errors.Is(err, http.ErrServerClosed) {
+				redirectErr <- err
+				s.server.Close()
+			}
+		}()
+	}
+
+	err := ignoreClosed(s.server.ServeTLS(ln, s.certFile, s.keyFile))
+	select {
+	case rerr := <-redirectErr:
+		return fmt.Errorf("httpserver: redirect listener: %w", rerr)
+	default:
+	}
+	if err != nil && s.redirect != nil {
+		s.redirect.Close()
+	}
+	return err
+}
+
+func ignoreClosed(err error) error {
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown gracefully stops the server and the redirect listener.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.redirect != nil {
+		s.redirect.Shutdown(ctx)
+	}
+	return s.server.Shutdown(ctx)
+}
+
+// Close immediately stops the server and the redirect listener.
+func (s *Server) Close() error {
+	if s.redirect != nil {
+		s.redirect.Close()
+	}
+	return s.server.Close()
+}
+
+// ParseTLSVersion converts a version string such as "1.2" into the
+// corresponding crypto/tls constant. An empty string defaults to TLS 1.2.
+func ParseTLSVersion(v string) (uint16, error) {
+	switch v {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("httpserver: unsupported TLS minimum version %q", v)
+	}
+}
+
+// RedirectHandler returns a handler that redirects every request to the same
+// host and path over HTTPS on the given port. It uses 308 so clients repeat
+// the original method and body.
+func RedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,281 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/config"
+	"github.com/veritas-protocol/veritas/services/pkg/httpjson"
+)
+
+// writeSelfSignedCert writes a localhost certificate and key to dir.
+func writeSelfSignedCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+
+	tests := []struct {
+		name    string
+		mutate  func(*config.AppConfig)
+		wantErr bool
+		wantTLS bool
+	}{
+		{name: "plain HTTP", mutate: func(c *config.AppConfig) {}},
+		{name: "TLS", mutate: func(c *config.AppConfig) { c.TLSCertFile, c.TLSKeyFile = certFile, keyFile }, wantTLS: true},
+		{name: "cert without key", mutate: func(c *config.AppConfig) { c.TLSCertFile = certFile }, wantErr: true},
+		{name: "key without cert", mutate: func(c *config.AppConfig) { c.TLSKeyFile = keyFile }, wantErr: true},
+		{name: "missing cert file", mutate: func(c *config.AppConfig) { c.TLSCertFile, c.TLSKeyFile = "/nonexistent.pem", keyFile }, wantErr: true},
+		{name: "bad min version", mutate: func(c *config.AppConfig) { c.TLSMinVersion = "1.0" }, wantErr: true},
+		{name: "zero body limit", mutate: func(c *config.AppConfig) { c.MaxBodyBytes = 0 }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig("test")
+			tt.mutate(&cfg)
+
+			s, err := New(":0", http.NotFoundHandler(), cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("New succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if s.TLS() != tt.wantTLS {
+				t.Errorf("TLS() = %v, want %v", s.TLS(), tt.wantTLS)
+			}
+		})
+	}
+}
+
+func TestNewAppliesBodyLimit(t *testing.T) {
+	cfg := config.DefaultConfig("test")
+	cfg.MaxBodyBytes = 16
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v map[string]string
+		if err := httpjson.Decode(w, r, &v); err != nil {
+			w.WriteHeader(httpjson.StatusCode(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s, err := New(":0", handler, cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"a longer name than sixteen bytes"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestServeTLSEnforcesMinVersion(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+
+	cfg := config.DefaultConfig("test")
+	cfg.TLSCertFile, cfg.TLSKeyFile = certFile, keyFile
+	cfg.TLSMinVersion = "1.3"
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s, err := New("127.0.0.1:0", handler, cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+	t.Cleanup(func() {
+		s.Close()
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+
+	url := "https://" + ln.Addr().String() + "/"
+	tests := []struct {
+		name       string
+		maxVersion uint16
+		wantErr    bool
+	}{
+		{name: "TLS 1.3 accepted", maxVersion: tls.VersionTLS13},
+		{name: "TLS 1.2 rejected", maxVersion: tls.VersionTLS12, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true, MaxVersion: tt.maxVersion},
+			}}
+			resp, err := client.Get(url)
+			if tt.wantErr {
+				if err == nil {
+					resp.Body.Close()
+					t.Fatal("request succeeded, want handshake failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GET: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNoContent {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+			}
+		})
+	}
+}
+
+func TestServeFailsWhenRedirectPortInUse(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	cfg := config.DefaultConfig("test")
+	cfg.TLSCertFile, cfg.TLSKeyFile = certFile, keyFile
+	cfg.TLSRedirectPort = busy.Addr().(*net.TCPAddr).Port
+
+	s, err := New("127.0.0.1:0", http.NotFoundHandler(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	// Bind the redirect to the busy address explicitly; New listens on all
+	// interfaces, which may not collide with a loopback-only listener.
+	s.redirect.Addr = busy.Addr().String()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Serve(ln); err == nil {
+		t.Fatal("Serve returned nil, want redirect listener error")
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		target    string
+		want      string
+	}{
+		{name: "custom port", httpsPort: "8443", target: "http://example.com:8080/api/v1/dids?page=2", want: "https://example.com:8443/api/v1/dids?page=2"},
+		{name: "default port", httpsPort: "443", target: "http://example.com/health", want: "https://example.com/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			RedirectHandler(tt.httpsPort).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.target, nil))
+
+			if rec.Code != http.StatusPermanentRedirect {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusPermanentRedirect)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Errorf("Location = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStart(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	cfg := config.DefaultConfig("test")
+
+	s, err := New(busy.Addr().String(), http.NotFoundHandler(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := s.Start(); err == nil {
+		t.Fatal("Start on a busy port succeeded, want error")
+	}
+
+	s, err = New("127.0.0.1:0", http.NotFoundHandler(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	select {
+	case err := <-s.Err():
+		if err != nil {
+			t.Errorf("Err() = %v after Shutdown, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Err() did not report after Shutdown")
+	}
+}
@@ -0,0 +1,35 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/veritas-protocol/veritas/services/pkg/httpjson"
+)
+
+// DisableRoutes wraps handler so that requests to any of the given routes
+// respond with 503 Service Unavailable. A route ending in "/" matches every
+// path beneath it; other routes match exactly.
+func DisableRoutes(handler http.Handler, routes []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, route := range routes {
+			if routeMatches(route, r.URL.Path) {
+				httpjson.Write(w, http.StatusServiceUnavailable, map[string]interface{}{
+					"error":   http.StatusText(http.StatusServiceUnavailable),
+					"code":    http.StatusServiceUnavailable,
+					"message": fmt.Sprintf("%s is temporarily disabled", r.URL.Path),
+				})
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func routeMatches(route, path string) bool {
+	if strings.HasSuffix(route, "/") {
+		return strings.HasPrefix(path, route)
+	}
+	return path == route
+}
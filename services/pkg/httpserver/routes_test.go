@@ -0,0 +1,57 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDisableRoutes(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := DisableRoutes(next, []string{"/api/v1/revoke", "/api/v1/webhooks/"})
+
+	tests := []struct {
+		path         string
+		wantDisabled bool
+	}{
+		{path: "/api/v1/revoke", wantDisabled: true},
+		{path: "/api/v1/revoke/extra"},
+		{path: "/api/v1/issue"},
+		{path: "/api/v1/webhooks/wh-000001", wantDisabled: true},
+		{path: "/api/v1/webhooks/", wantDisabled: true},
+		{path: "/api/v1/webhooks"},
+		{path: "/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, nil))
+
+			if !tt.wantDisabled {
+				if rec.Code != http.StatusTeapot {
+					t.Errorf("status = %d, want the wrapped handler's %d", rec.Code, http.StatusTeapot)
+				}
+				return
+			}
+
+			if rec.Code != http.StatusServiceUnavailable {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+			}
+			var body struct {
+				Error   string `json:"error"`
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body is not JSON: %v", err)
+			}
+			if body.Code != http.StatusServiceUnavailable || body.Message != tt.path+" is temporarily disabled" {
+				t.Errorf("body = %+v", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Package lifecycle coordinates startup and ordered shutdown of the
+// components that make up a Veritas service.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout is the deadline services give Shutdown.
+const DefaultShutdownTimeout = 10 * time.Second
+
+// StartFunc starts a component. It must not block.
+type StartFunc func() error
+
+// StopFunc stops a component, returning once it has stopped or ctx is done.
+type StopFunc func(ctx context.Context) error
+
+type hook struct {
+	name  string
+	start StartFunc
+	stop  StopFunc
+}
+
+// Lifecycle holds registered components. Components are started in
+// registration order and stopped in reverse order.
+type Lifecycle struct {
+	mu     sync.Mutex
+	hooks  []hook
+	logger *slog.Logger
+}
+
+// New creates a Lifecycle that reports shutdown progress to logger.
+func New(logger *slog.Logger) *Lifecycle {
+	return &Lifecycle{logger: logger}
+}
+
+// Register adds a component. Either hook may be nil.
+func (l *Lifecycle) Register(name string, start StartFunc, stop StopFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook{name: name, start: start, stop: stop})
+}
+
+// Start runs the start hooks in registration order. If one fails, the
+// components already started are stopped again and the error is returned.
+func (l *Lifecycle) Start() error {
+	l.mu.Lock()
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	for i, h := range hooks {
+		if h.start == nil {
+			continue
+		}
+		if err := h.start(); err != nil {
+			l.stop(hooks[:i], DefaultShutdownTimeout)
+			return fmt.Errorf("lifecycle: failed to start %s: %w", h.name, err)
+		}
+	}
+	return nil
+}
+
+// Shutdown runs the stop hooks of all registered components in reverse
+// registration order, sharing a single deadline of timeout. A hook still
+// running when the deadline passes is logged and abandoned so that shutdown
+// cannot hang.
+func (l *Lifecycle) Shutdown(timeout time.Duration) error {
+	l.mu.Lock()
+	hooks := l.hooks
+	l.hooks = nil
+	l.mu.Unlock()
+
+	return l.stop(hooks, timeout)
+}
+
+func (l *Lifecycle) stop(hooks []hook, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.stop == nil {
+			continue
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- h.stop(ctx) }()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				l.logger.Error("component failed to stop", "component", h.name, "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			}
+		case <-ctx.Done():
+			l.logger.Error("component did not stop before shutdown deadline", "component", h.name)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, ctx.Err()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WaitForSignal blocks until the process receives SIGINT or SIGTERM and
+// returns the signal.
+func WaitForSignal() os.Signal {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	return <-sigCh
+}
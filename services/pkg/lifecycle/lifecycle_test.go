@@ -0,0 +1,151 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects the order in which hooks run.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) add(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func (r *recorder) start(name string, err error) StartFunc {
+	return func() error {
+		r.add("start " + name)
+		return err
+	}
+}
+
+func (r *recorder) stop(name string) StopFunc {
+	return func(ctx context.Context) error {
+		r.add("stop " + name)
+		return nil
+	}
+}
+
+func newTestLifecycle() (*Lifecycle, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return New(slog.New(slog.NewTextHandler(&buf, nil))), &buf
+}
+
+func TestLifecycleStartAndShutdownOrder(t *testing.T) {
+	rec := &recorder{}
+	lc, _ := newTestLifecycle()
+	lc.Register("store", rec.start("store", nil), rec.stop("store"))
+	lc.Register("sweeper", nil, rec.stop("sweeper"))
+	lc.Register("http-server", rec.start("http-server", nil), rec.stop("http-server"))
+
+	if err := lc.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := lc.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	want := []string{
+		"start store", "start http-server",
+		"stop http-server", "stop sweeper", "stop store",
+	}
+	if got := rec.get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestLifecycleStartFailureStopsStartedComponents(t *testing.T) {
+	tests := []struct {
+		name      string
+		failing   string
+		wantCalls []string
+	}{
+		{
+			name:      "first component fails",
+			failing:   "store",
+			wantCalls: []string{"start store"},
+		},
+		{
+			name:      "later component fails",
+			failing:   "http-server",
+			wantCalls: []string{"start store", "start metrics", "start http-server", "stop metrics", "stop store"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			lc, _ := newTestLifecycle()
+			for _, name := range []string{"store", "metrics", "http-server"} {
+				var err error
+				if name == tt.failing {
+					err = errors.New("bind: address already in use")
+				}
+				lc.Register(name, rec.start(name, err), rec.stop(name))
+			}
+
+			err := lc.Start()
+			if err == nil || !strings.Contains(err.Error(), tt.failing) {
+				t.Fatalf("Start error = %v, want one naming %s", err, tt.failing)
+			}
+			if got := rec.get(); !reflect.DeepEqual(got, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestLifecycleShutdownDeadline(t *testing.T) {
+	lc, logs := newTestLifecycle()
+	release := make(chan struct{})
+	defer close(release)
+
+	lc.Register("stuck-worker", nil, func(ctx context.Context) error {
+		// Ignores ctx, as a misbehaving component would.
+		<-release
+		return nil
+	})
+	lc.Register("failing", nil, func(ctx context.Context) error {
+		return errors.New("flush failed")
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- lc.Shutdown(50 * time.Millisecond) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown hung on a hook that ignores its deadline")
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown error = %v, want it to wrap context.DeadlineExceeded", err)
+	}
+	for _, want := range []string{"stuck-worker", "failing", "flush failed"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Shutdown error %q does not mention %q", err, want)
+		}
+	}
+	if !strings.Contains(logs.String(), "component=stuck-worker") {
+		t.Errorf("stuck component was not logged:\n%s", logs)
+	}
+}
@@ -3,6 +3,7 @@
 package logging
 
 import (
+	"io"
 	"log/slog"
 	"os"
 	"strings"
@@ -12,6 +13,12 @@ import (
 // service name and log level. The logger outputs JSON-formatted log entries
 // to stdout.
 func Setup(serviceName, level string) *slog.Logger {
+	return SetupWithWriter(os.Stdout, serviceName, level)
+}
+
+// SetupWithWriter is like Setup but writes JSON-formatted log entries to w,
+// which lets tests capture log output in a buffer.
+func SetupWithWriter(w io.Writer, serviceName, level string) *slog.Logger {
 	var logLevel slog.Level
 	switch strings.ToLower(level) {
 	case "debug":
@@ -26,7 +33,7 @@ func Setup(serviceName, level string) *slog.Logger {
 		logLevel = slog.LevelInfo
 	}
 
-	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
 		Level: logLevel,
 	})
 
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestSetupWithWriter(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     string
+		wantLevel string
+	}{
+		{name: "info", level: "info", wantLevel: "INFO"},
+		{name: "unknown level falls back to info", level: "verbose", wantLevel: "INFO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := SetupWithWriter(&buf, "registry-api", tt.level)
+			logger.Info("resolved DID", "did", "did:veritas:abc")
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("log line is not JSON: %v\n%s", err, buf.String())
+			}
+
+			want := map[string]string{
+				"service": "registry-api",
+				"level":   tt.wantLevel,
+				"msg":     "resolved DID",
+				"did":     "did:veritas:abc",
+			}
+			for key, value := range want {
+				if entry[key] != value {
+					t.Errorf("%s = %v, want %q", key, entry[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestSetupWithWriterFiltersByLevel(t *testing.T) {
+	tests := []struct {
+		level       string
+		wantDebug   bool
+		wantInfo    bool
+		wantWarning bool
+	}{
+		{level: "debug", wantDebug: true, wantInfo: true, wantWarning: true},
+		{level: "info", wantInfo: true, wantWarning: true},
+		{level: "warn", wantWarning: true},
+		{level: "WARNING", wantWarning: true},
+		{level: "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			var debug, info, warn bytes.Buffer
+			SetupWithWriter(&debug, "svc", tt.level).Debug("d")
+			SetupWithWriter(&info, "svc", tt.level).Info("i")
+			SetupWithWriter(&warn, "svc", tt.level).Warn("w")
+
+			if got := debug.Len() > 0; got != tt.wantDebug {
+				t.Errorf("debug logged = %v, want %v", got, tt.wantDebug)
+			}
+			if got := info.Len() > 0; got != tt.wantInfo {
+				t.Errorf("info logged = %v, want %v", got, tt.wantInfo)
+			}
+			if got := warn.Len() > 0; got != tt.wantWarning {
+				t.Errorf("warn logged = %v, want %v", got, tt.wantWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/veritas-protocol/veritas/services/pkg/config"
+)
+
+func passing(name string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error { return nil }}
+}
+
+func failing(name, msg string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error { return errors.New(msg) }}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []Check
+		wantPassed bool
+		wantFailed []string
+	}{
+		{name: "no checks", wantPassed: true},
+		{name: "all pass", checks: []Check{passing("config"), passing("health")}, wantPassed: true},
+		{name: "one fails", checks: []Check{passing("config"), failing("registry", "connection refused"), passing("health")}, wantFailed: []string{"registry"}},
+		{name: "all fail", checks: []Check{failing("config", "bad port"), failing("health", "503")}, wantFailed: []string{"config", "health"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Run(context.Background(), "verifier-api", tt.checks...)
+
+			if report.Service != "verifier-api" {
+				t.Errorf("service = %q", report.Service)
+			}
+			if report.Passed != tt.wantPassed {
+				t.Errorf("passed = %v, want %v", report.Passed, tt.wantPassed)
+			}
+			if len(report.Results) != len(tt.checks) {
+				t.Fatalf("%d results, want %d", len(report.Results), len(tt.checks))
+			}
+
+			var failed []string
+			for i, r := range report.Results {
+				if r.Name != tt.checks[i].Name {
+					t.Errorf("result %d is %q, want checks run in order", i, r.Name)
+				}
+				if !r.Passed {
+					failed = append(failed, r.Name)
+					if r.Error == "" {
+						t.Errorf("failed check %s has no error", r.Name)
+					}
+				}
+			}
+			if len(failed) != len(tt.wantFailed) {
+				t.Fatalf("failed = %v, want %v", failed, tt.wantFailed)
+			}
+			for i := range failed {
+				if failed[i] != tt.wantFailed[i] {
+					t.Errorf("failed = %v, want %v", failed, tt.wantFailed)
+				}
+			}
+		})
+	}
+}
+
+func TestMainExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		checks   []Check
+		wantCode int
+	}{
+		{name: "pass", checks: []Check{passing("config")}, wantCode: 0},
+		{name: "fail", checks: []Check{passing("config"), failing("issuer", "timeout")}, wantCode: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if code := Main(&buf, "gateway", tt.checks...); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			var report Report
+			if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+				t.Fatalf("output is not a JSON report: %v", err)
+			}
+			if report.Passed != (tt.wantCode == 0) {
+				t.Errorf("report passed = %v", report.Passed)
+			}
+		})
+	}
+}
+
+func TestChecks(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL := down.URL
+	down.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+
+	badConfig := config.DefaultConfig("test")
+	badConfig.TLSCertFile = "/only/a/cert.pem"
+
+	tests := []struct {
+		name    string
+		check   Check
+		wantErr bool
+	}{
+		{name: "valid config", check: ConfigCheck(config.DefaultConfig("test"))},
+		{name: "invalid config", check: ConfigCheck(badConfig), wantErr: true},
+		{name: "handler ok", check: HandlerCheck("health", mux, "/health")},
+		{name: "handler missing route", check: HandlerCheck("stats", mux, "/api/v1/stats"), wantErr: true},
+		{name: "upstream healthy", check: UpstreamCheck("issuer", upstream.URL)},
+		{name: "upstream trailing slash", check: UpstreamCheck("issuer", upstream.URL+"/")},
+		{name: "upstream unhealthy", check: UpstreamCheck("issuer", unhealthy.URL), wantErr: true},
+		{name: "upstream unreachable", check: UpstreamCheck("registry", downURL), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check.Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package zkp provides lightweight zero-knowledge style proofs used by the
+// Veritas services.
+package zkp
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MaxThresholdValue bounds the values a threshold commitment can hold, which
+// also bounds the hash-chain length a verifier will walk.
+const MaxThresholdValue = 1000
+
+// ErrThresholdNotMet is returned when proving a value below the minimum.
+var ErrThresholdNotMet = errors.New("value does not meet the required minimum")
+
+// ThresholdSecret is the holder's side of a threshold commitment to a value.
+//
+// It is a hash-chain commitment: for a secret random seed, the commitment is
+// H^value(seed). A proof that value >= min reveals the witness
+// H^(value-min)(seed), and the verifier checks that hashing it min more
+// times yields the commitment. Producing a witness for a min above the
+// committed value would require inverting the hash, so the commitment must
+// come from someone other than the prover: the credential issuer signs it in
+// an Attestation.
+type ThresholdSecret struct {
+	seed  []byte
+	value int
+}
+
+// NewThresholdSecret commits to value with a fresh random seed.
+func NewThresholdSecret(value int) (ThresholdSecret, error) {
+	if value < 0 || value > MaxThresholdValue {
+		return ThresholdSecret{}, fmt.Errorf("zkp: value must be between 0 and %d", MaxThresholdValue)
+	}
+
+	seed := make([]byte, sha256.Size)
+	if _, err := rand.Read(seed); err != nil {
+		return ThresholdSecret{}, fmt.Errorf("zkp: failed to generate seed: %w", err)
+	}
+	return ThresholdSecret{seed: seed, value: value}, nil
+}
+
+// Commitment returns the hex-encoded commitment to the secret's value.
+func (s ThresholdSecret) Commitment() string {
+	return hex.EncodeToString(hashChain(s.seed, s.value))
+}
+
+// Witness returns the hex-encoded witness proving the committed value is at
+// least minimum.
+func (s ThresholdSecret) Witness(minimum int) (string, error) {
+	if minimum < 0 || minimum > MaxThresholdValue {
+		return "", fmt.Errorf("zkp: minimum must be between 0 and %d", MaxThresholdValue)
+	}
+	if s.value < minimum {
+		return "", ErrThresholdNotMet
+	}
+	return hex.EncodeToString(hashChain(s.seed, s.value-minimum)), nil
+}
+
+// Attestation is an issuer's statement that Commitment is a threshold
+// commitment to the Claim value of credential CredentialID. Challenge, when
+// set, is the verifier challenge the attestation was signed for, so a proof
+// built on it is only good for that one verification.
+type Attestation struct {
+	CredentialID string `json:"credential_id"`
+	Issuer       string `json:"issuer"`
+	Subject      string `json:"subject"`
+	Claim        string `json:"claim"`
+	Commitment   string `json:"commitment"`
+	Challenge    string `json:"challenge,omitempty"`
+}
+
+// SignedAttestation carries the exact bytes that were signed in Payload
+// (base64 of the attestation's JSON encoding). KeyID names the verification
+// method in the issuer's DID document whose key must be used to check
+// Signature.
+type SignedAttestation struct {
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+	KeyID     string `json:"key_id"`
+}
+
+// SignAttestation signs a with key, published under keyID.
+func SignAttestation(a Attestation, keyID string, key ed25519.PrivateKey) (SignedAttestation, error) {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return SignedAttestation{}, err
+	}
+	return SignedAttestation{
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+		KeyID:     keyID,
+	}, nil
+}
+
+// Verify checks that the attestation was signed by pub and returns it.
+func (s SignedAttestation) Verify(pub ed25519.PublicKey) (Attestation, error) {
+	payload, err := base64.StdEncoding.DecodeString(s.Payload)
+	if err != nil {
+		return Attestation{}, fmt.Errorf("zkp: invalid attestation encoding: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(s.Signature)
+	if err != nil {
+		return Attestation{}, fmt.Errorf("zkp: invalid signature encoding: %w", err)
+	}
+	if len(pub) != ed25519.PublicKeySize || !ed25519.Verify(pub, payload, sig) {
+		return Attestation{}, errors.New("zkp: attestation signature does not match")
+	}
+
+	var a Attestation
+	if err := json.Unmarshal(payload, &a); err != nil {
+		return Attestation{}, fmt.Errorf("zkp: invalid attestation: %w", err)
+	}
+	return a, nil
+}
+
+// ThresholdProof proves that the value committed in an issuer-signed
+// attestation is at least Min without revealing the value.
+type ThresholdProof struct {
+	Attestation SignedAttestation `json:"attestation"`
+	Witness     string            `json:"witness"`
+	Min         int               `json:"min"`
+}
+
+// ProveThreshold creates a proof that the value committed by secret, and
+// attested in attestation, is at least minimum.
+func ProveThreshold(secret ThresholdSecret, attestation SignedAttestation, minimum int) (ThresholdProof, error) {
+	witness, err := secret.Witness(minimum)
+	if err != nil {
+		return ThresholdProof{}, err
+	}
+	return ThresholdProof{Attestation: attestation, Witness: witness, Min: minimum}, nil
+}
+
+// VerifyThreshold checks that proof's attestation was signed by issuerKey and
+// that its committed value is at least proof.Min. It returns the attestation
+// so the caller can check which credential and claim it covers.
+func VerifyThreshold(proof ThresholdProof, issuerKey ed25519.PublicKey) (Attestation, error) {
+	if proof.Min < 0 || proof.Min > MaxThresholdValue {
+		return Attestation{}, fmt.Errorf("zkp: minimum must be between 0 and %d", MaxThresholdValue)
+	}
+
+	attestation, err := proof.Attestation.Verify(issuerKey)
+	if err != nil {
+		return Attestation{}, err
+	}
+
+	commitment, err := hex.DecodeString(attestation.Commitment)
+	if err != nil || len(commitment) != sha256.Size {
+		return Attestation{}, errors.New("zkp: malformed commitment")
+	}
+	witness, err := hex.DecodeString(proof.Witness)
+	if err != nil || len(witness) != sha256.Size {
+		return Attestation{}, errors.New("zkp: malformed witness")
+	}
+
+	if !bytes.Equal(hashChain(witness, proof.Min), commitment) {
+		return Attestation{}, errors.New("zkp: witness does not match commitment")
+	}
+	return attestation, nil
+}
+
+// hashChain applies SHA-256 to b n times.
+func hashChain(b []byte, n int) []byte {
+	out := append([]byte(nil), b...)
+	for i := 0; i < n; i++ {
+		sum := sha256.Sum256(out)
+		out = sum[:]
+	}
+	return out
+}
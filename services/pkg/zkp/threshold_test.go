@@ -0,0 +1,145 @@
+package zkp
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func newTestKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv
+}
+
+// attest commits to value and returns the secret with an attestation signed
+// by key.
+func attest(t *testing.T, key ed25519.PrivateKey, value int) (ThresholdSecret, SignedAttestation) {
+	t.Helper()
+	secret, err := NewThresholdSecret(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := SignAttestation(Attestation{
+		CredentialID: "gw-vc-000001",
+		Issuer:       "did:veritas:key:gateway",
+		Subject:      "did:veritas:holder",
+		Claim:        "kyc_level",
+		Commitment:   secret.Commitment(),
+	}, "did:veritas:key:gateway#attestation-key", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret, signed
+}
+
+func TestProveThreshold(t *testing.T) {
+	issuer := newTestKey(t)
+
+	tests := []struct {
+		name    string
+		value   int
+		minimum int
+		wantErr error
+	}{
+		{name: "level 3 satisfies min 2", value: 3, minimum: 2},
+		{name: "level 2 satisfies min 2", value: 2, minimum: 2},
+		{name: "min 0", value: 1, minimum: 0},
+		{name: "level 1 fails min 2", value: 1, minimum: 2, wantErr: ErrThresholdNotMet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret, attestation := attest(t, issuer, tt.value)
+			proof, err := ProveThreshold(secret, attestation, tt.minimum)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ProveThreshold error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProveThreshold: %v", err)
+			}
+
+			got, err := VerifyThreshold(proof, issuer.Public().(ed25519.PublicKey))
+			if err != nil {
+				t.Fatalf("VerifyThreshold: %v", err)
+			}
+			if got.CredentialID != "gw-vc-000001" || got.Claim != "kyc_level" {
+				t.Errorf("attestation = %+v", got)
+			}
+		})
+	}
+}
+
+func TestVerifyThresholdRejectsForgeries(t *testing.T) {
+	issuer := newTestKey(t)
+	issuerPub := issuer.Public().(ed25519.PublicKey)
+	forger := newTestKey(t)
+
+	secret, attestation := attest(t, issuer, 1)
+	honest, err := ProveThreshold(secret, attestation, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A self-made chain: any witness W with commitment H^min(W).
+	zero := make([]byte, 32)
+	_, forgedAttestation := attest(t, forger, 0)
+	selfSigned, err := SignAttestation(Attestation{
+		CredentialID: "gw-vc-000001",
+		Claim:        "kyc_level",
+		Commitment:   hex.EncodeToString(hashChain(zero, MaxThresholdValue)),
+	}, "did:veritas:key:gateway#attestation-key", forger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		proof ThresholdProof
+	}{
+		{
+			name:  "self-made chain signed by another key",
+			proof: ThresholdProof{Attestation: selfSigned, Witness: hex.EncodeToString(zero), Min: MaxThresholdValue},
+		},
+		{
+			name:  "attestation signed by another key",
+			proof: ThresholdProof{Attestation: forgedAttestation, Witness: honest.Witness, Min: 1},
+		},
+		{
+			name:  "min raised above the committed value",
+			proof: ThresholdProof{Attestation: attestation, Witness: honest.Witness, Min: 2},
+		},
+		{
+			name:  "witness from another commitment",
+			proof: ThresholdProof{Attestation: attestation, Witness: hex.EncodeToString(zero), Min: 1},
+		},
+		{
+			name:  "min out of range",
+			proof: ThresholdProof{Attestation: attestation, Witness: honest.Witness, Min: MaxThresholdValue + 1},
+		},
+		{
+			name: "tampered attestation payload",
+			proof: ThresholdProof{
+				Attestation: SignedAttestation{Payload: selfSigned.Payload, Signature: attestation.Signature},
+				Witness:     hex.EncodeToString(zero),
+				Min:         MaxThresholdValue,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyThreshold(tt.proof, issuerPub); err == nil {
+				t.Fatal("VerifyThreshold accepted a forged proof")
+			}
+		})
+	}
+}
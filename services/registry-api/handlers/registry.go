@@ -2,45 +2,74 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+	"github.com/veritas-protocol/veritas/services/pkg/httpjson"
 )
 
 // DidRecord represents a registered DID document in the registry.
 type DidRecord struct {
-	DID        string                 `json:"did"`
-	Document   map[string]interface{} `json:"document"`
-	RegisteredAt time.Time            `json:"registered_at"`
+	DID          string                 `json:"did"`
+	Document     map[string]interface{} `json:"document"`
+	RegisteredAt time.Time              `json:"registered_at"`
 }
 
 // SchemaRecord represents a registered credential schema.
 type SchemaRecord struct {
-	ID          string                 `json:"id"`
-	Name        string                 `json:"name"`
-	Version     string                 `json:"version"`
-	Claims      []string               `json:"claims"`
-	RegisteredAt time.Time             `json:"registered_at"`
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Version      string    `json:"version"`
+	Claims       []string  `json:"claims"`
+	RegisteredAt time.Time `json:"registered_at"`
 }
 
 // RegistryHandler handles registry API endpoints.
 type RegistryHandler struct {
-	mu      sync.RWMutex
-	dids    map[string]*DidRecord
-	schemas map[string]*SchemaRecord
+	mu        sync.RWMutex
+	dids      map[string]*DidRecord
+	schemas   map[string]*SchemaRecord
+	resolvers map[string]MethodResolver
+	clock     clock.Clock
+
+	batchTimeout time.Duration
 }
 
-// NewRegistryHandler creates a new RegistryHandler.
+// NewRegistryHandler creates a new RegistryHandler that resolves every DID
+// from its local store. Remote methods such as did:web are opt-in through
+// RegisterResolver, since resolving them makes the registry fetch URLs chosen
+// by the caller.
 func NewRegistryHandler() *RegistryHandler {
 	return &RegistryHandler{
-		dids:    make(map[string]*DidRecord),
-		schemas: make(map[string]*SchemaRecord),
+		dids:      make(map[string]*DidRecord),
+		schemas:   make(map[string]*SchemaRecord),
+		resolvers: make(map[string]MethodResolver),
+		clock:     clock.New(),
+
+		batchTimeout: ResolveBatchTimeout,
 	}
 }
 
+// RegisterResolver makes DIDs of the given method (e.g. "web") resolve
+// through resolver instead of the local store.
+func (h *RegistryHandler) RegisterResolver(method string, resolver MethodResolver) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.resolvers[method] = resolver
+}
+
+// UseClock sets the time source used for registration timestamps.
+func (h *RegistryHandler) UseClock(c clock.Clock) {
+	h.clock = c
+}
+
 // HandleDids handles POST /api/v1/dids (register) and GET /api/v1/dids (list).
 func (h *RegistryHandler) HandleDids(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -60,22 +89,186 @@ func (h *RegistryHandler) HandleDidByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	did := strings.TrimPrefix(r.URL.Path, "/api/v1/dids/")
-	if did == "" {
-		writeError(w, http.StatusBadRequest, "DID is required")
+	did, err := httpjson.PathParam(r.URL.Path, "/api/v1/dids/", "DID")
+	if err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
+		return
+	}
+
+	record, err := h.resolve(r.Context(), did)
+	if errors.Is(err, ErrDIDNotFound) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("DID %s not found", did))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("failed to resolve %s: %v", did, err))
 		return
 	}
 
+	if record.RegisteredAt.IsZero() {
+		// Resolved by a method resolver, not registered here.
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"did":      record.DID,
+			"document": record.Document,
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, record)
+}
+
+// resolve dispatches to the resolver registered for the DID's method,
+// falling back to the local store (which holds did:veritas documents).
+// Remotely resolved records have no RegisteredAt.
+func (h *RegistryHandler) resolve(ctx context.Context, did string) (*DidRecord, error) {
+	// Identifiers without a parseable method can only live in the local store.
+	method, _ := DIDMethod(did)
+
 	h.mu.RLock()
+	resolver, hasResolver := h.resolvers[method]
 	record, exists := h.dids[did]
 	h.mu.RUnlock()
 
+	if hasResolver {
+		doc, err := resolver.Resolve(ctx, did)
+		if err != nil {
+			return nil, err
+		}
+		return &DidRecord{DID: did, Document: doc}, nil
+	}
+
 	if !exists {
-		writeError(w, http.StatusNotFound, fmt.Sprintf("DID %s not found", did))
+		return nil, ErrDIDNotFound
+	}
+	return record, nil
+}
+
+const (
+	// MaxResolveBatchSize is the largest number of DIDs accepted by
+	// HandleResolveBatch.
+	MaxResolveBatchSize = 100
+
+	// ResolveBatchTimeout bounds the time HandleResolveBatch spends
+	// resolving; DIDs still pending when it expires are reported as errors.
+	ResolveBatchTimeout = 15 * time.Second
+
+	// maxConcurrentResolutions bounds how many DIDs of one batch are
+	// resolved at once.
+	maxConcurrentResolutions = 8
+)
+
+// ResolveBatchRequest is the body of POST /api/v1/dids/resolve-batch. Each
+// element is decoded separately so one malformed entry does not fail the
+// whole batch.
+type ResolveBatchRequest struct {
+	DIDs []json.RawMessage `json:"dids"`
+}
+
+// BatchItemError reports an element of a batch request that could not be
+// processed.
+type BatchItemError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// ResolveResult is the resolution outcome for one DID in a batch.
+type ResolveResult struct {
+	Status   string                 `json:"status"`
+	Document map[string]interface{} `json:"document,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+// HandleResolveBatch handles POST /api/v1/dids/resolve-batch.
+func (h *RegistryHandler) HandleResolveBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, record)
+	var req ResolveBatchRequest
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
+		return
+	}
+
+	if len(req.DIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "dids is required")
+		return
+	}
+	if len(req.DIDs) > MaxResolveBatchSize {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d DIDs may be resolved per request", MaxResolveBatchSize))
+		return
+	}
+
+	itemErrors := make([]BatchItemError, 0)
+	var dids []string
+	seen := make(map[string]bool, len(req.DIDs))
+
+	for i, raw := range req.DIDs {
+		var did string
+		if err := json.Unmarshal(raw, &did); err != nil {
+			itemErrors = append(itemErrors, BatchItemError{Index: i, Error: fmt.Sprintf("invalid DID: %v", err)})
+			continue
+		}
+		if did == "" {
+			itemErrors = append(itemErrors, BatchItemError{Index: i, Error: "DID is required"})
+			continue
+		}
+		// Repeated DIDs are resolved once.
+		if !seen[did] {
+			seen[did] = true
+			dids = append(dids, did)
+		}
+	}
+
+	results := h.resolveAll(r.Context(), dids)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"results": results,
+		"errors":  itemErrors,
+		"count":   len(results),
+	})
+}
+
+// resolveAll resolves dids concurrently under ResolveBatchTimeout.
+func (h *RegistryHandler) resolveAll(ctx context.Context, dids []string) map[string]ResolveResult {
+	ctx, cancel := context.WithTimeout(ctx, h.batchTimeout)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]ResolveResult, len(dids))
+		sem     = make(chan struct{}, maxConcurrentResolutions)
+	)
+	for _, did := range dids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(did string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			var result ResolveResult
+			record, err := h.resolve(ctx, did)
+			switch {
+			case errors.Is(err, ErrDIDNotFound):
+				result = ResolveResult{Status: "not_found"}
+			case err != nil:
+				result = ResolveResult{Status: "error", Error: err.Error()}
+			default:
+				result = ResolveResult{Status: "found", Document: record.Document}
+			}
+
+			mu.Lock()
+			results[did] = result
+			mu.Unlock()
+		}(did)
+	}
+	wg.Wait()
+
+	return results
 }
 
 // HandleSchemas handles POST /api/v1/schemas (register) and GET /api/v1/schemas (list).
@@ -114,8 +307,8 @@ func (h *RegistryHandler) registerDid(w http.ResponseWriter, r *http.Request) {
 		DID      string                 `json:"did"`
 		Document map[string]interface{} `json:"document"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 	if req.DID == "" {
@@ -123,7 +316,7 @@ func (h *RegistryHandler) registerDid(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	now := time.Now().UTC()
+	now := h.clock.Now().UTC()
 	record := &DidRecord{
 		DID:          req.DID,
 		Document:     req.Document,
@@ -138,6 +331,12 @@ func (h *RegistryHandler) registerDid(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *RegistryHandler) listDids(w http.ResponseWriter, r *http.Request) {
+	page, err := httpjson.ParsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	h.mu.RLock()
 	dids := make([]DidRecord, 0, len(h.dids))
 	for _, d := range h.dids {
@@ -145,16 +344,29 @@ func (h *RegistryHandler) listDids(w http.ResponseWriter, r *http.Request) {
 	}
 	h.mu.RUnlock()
 
+	// Map iteration order is random; sort so paging and diffs are stable.
+	sort.Slice(dids, func(i, j int) bool {
+		if !dids[i].RegisteredAt.Equal(dids[j].RegisteredAt) {
+			return dids[i].RegisteredAt.Before(dids[j].RegisteredAt)
+		}
+		return dids[i].DID < dids[j].DID
+	})
+
+	start, end := page.Bounds(len(dids))
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"dids":  dids,
-		"count": len(dids),
+		"dids":        dids[start:end],
+		"count":       len(dids),
+		"page_count":  end - start,
+		"total":       len(dids),
+		"next_cursor": page.NextCursor(len(dids)),
 	})
 }
 
 func (h *RegistryHandler) registerSchema(w http.ResponseWriter, r *http.Request) {
 	var req SchemaRecord
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 	if req.ID == "" {
@@ -162,7 +374,7 @@ func (h *RegistryHandler) registerSchema(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	req.RegisteredAt = time.Now().UTC()
+	req.RegisteredAt = h.clock.Now().UTC()
 
 	h.mu.Lock()
 	h.schemas[req.ID] = &req
@@ -172,6 +384,12 @@ func (h *RegistryHandler) registerSchema(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *RegistryHandler) listSchemas(w http.ResponseWriter, r *http.Request) {
+	page, err := httpjson.ParsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	h.mu.RLock()
 	schemas := make([]SchemaRecord, 0, len(h.schemas))
 	for _, s := range h.schemas {
@@ -179,16 +397,26 @@ func (h *RegistryHandler) listSchemas(w http.ResponseWriter, r *http.Request) {
 	}
 	h.mu.RUnlock()
 
+	sort.Slice(schemas, func(i, j int) bool {
+		if !schemas[i].RegisteredAt.Equal(schemas[j].RegisteredAt) {
+			return schemas[i].RegisteredAt.Before(schemas[j].RegisteredAt)
+		}
+		return schemas[i].ID < schemas[j].ID
+	})
+
+	start, end := page.Bounds(len(schemas))
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"schemas": schemas,
-		"count":   len(schemas),
+		"schemas":     schemas[start:end],
+		"count":       len(schemas),
+		"page_count":  end - start,
+		"total":       len(schemas),
+		"next_cursor": page.NextCursor(len(schemas)),
 	})
 }
 
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+	httpjson.Write(w, status, data)
 }
 
 func writeError(w http.ResponseWriter, status int, message string) {
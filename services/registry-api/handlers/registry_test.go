@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+	"github.com/veritas-protocol/veritas/services/pkg/httpjson"
+)
+
+// newTestRegistry returns a RegistryHandler driven by a fake clock.
+func newTestRegistry() (*RegistryHandler, *clock.FakeClock) {
+	fc := clock.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	h := NewRegistryHandler()
+	h.UseClock(fc)
+	return h, fc
+}
+
+func serve(t *testing.T, handler http.HandlerFunc, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func registerDID(t *testing.T, h *RegistryHandler, did string) {
+	t.Helper()
+	body := `{"did":"` + did + `","document":{"id":"` + did + `"}}`
+	if rec := serve(t, h.HandleDids, http.MethodPost, "/api/v1/dids", body); rec.Code != http.StatusCreated {
+		t.Fatalf("register %s status = %d: %s", did, rec.Code, rec.Body)
+	}
+}
+
+func registerSchema(t *testing.T, h *RegistryHandler, id string) {
+	t.Helper()
+	body := `{"id":"` + id + `","name":"KYC","version":"1.0","claims":["kyc_level"]}`
+	if rec := serve(t, h.HandleSchemas, http.MethodPost, "/api/v1/schemas", body); rec.Code != http.StatusCreated {
+		t.Fatalf("register %s status = %d: %s", id, rec.Code, rec.Body)
+	}
+}
+
+// listIDs lists target and returns the value of idField for each entry of
+// the listField array.
+func listIDs(t *testing.T, handler http.HandlerFunc, target, listField, idField string) []string {
+	t.Helper()
+	rec := serve(t, handler, http.MethodGet, target, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(resp[listField], &entries); err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i], _ = e[idField].(string)
+	}
+	return ids
+}
+
+func TestListOrderIsStable(t *testing.T) {
+	tests := []struct {
+		name      string
+		register  func(*testing.T, *RegistryHandler, string)
+		list      func(*RegistryHandler) http.HandlerFunc
+		target    string
+		listField string
+		idField   string
+		idFormat  string
+	}{
+		{
+			name:      "dids",
+			register:  registerDID,
+			list:      func(h *RegistryHandler) http.HandlerFunc { return h.HandleDids },
+			target:    "/api/v1/dids?page_size=100",
+			listField: "dids",
+			idField:   "did",
+			idFormat:  "did:veritas:%03d",
+		},
+		{
+			name:      "schemas",
+			register:  registerSchema,
+			list:      func(h *RegistryHandler) http.HandlerFunc { return h.HandleSchemas },
+			target:    "/api/v1/schemas?page_size=100",
+			listField: "schemas",
+			idField:   "id",
+			idFormat:  "kyc-%03d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fc := newTestRegistry()
+
+			// Registered in descending ID order, several per timestamp, so
+			// the expected order depends on both sort keys.
+			var want []string
+			for tick := 0; tick < 6; tick++ {
+				fc.Advance(time.Second)
+				var batch []string
+				for i := 4; i >= 0; i-- {
+					id := fmt.Sprintf(tt.idFormat, 100-tick*5+i)
+					tt.register(t, h, id)
+					batch = append([]string{id}, batch...)
+				}
+				want = append(want, batch...)
+			}
+
+			first := listIDs(t, tt.list(h), tt.target, tt.listField, tt.idField)
+			if !reflect.DeepEqual(first, want) {
+				t.Fatalf("order = %v, want %v", first, want)
+			}
+			if second := listIDs(t, tt.list(h), tt.target, tt.listField, tt.idField); !reflect.DeepEqual(second, first) {
+				t.Errorf("second call order = %v, want %v", second, first)
+			}
+		})
+	}
+}
+
+// failingResolver fails every resolution, like an unreachable did:web host.
+type failingResolver struct{}
+
+func (failingResolver) Resolve(ctx context.Context, did string) (map[string]interface{}, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestResolveBatchResponseShape(t *testing.T) {
+	h, _ := newTestRegistry()
+	h.RegisterResolver("unreachable", failingResolver{})
+	registerDID(t, h, "did:veritas:alice")
+
+	body := `{"dids":["did:veritas:alice","did:veritas:unknown","did:unreachable:example",42,""]}`
+	rec := serve(t, h.HandleResolveBatch, http.MethodPost, "/api/v1/dids/resolve-batch", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Results map[string]map[string]interface{} `json:"results"`
+		Errors  []BatchItemError                  `json:"errors"`
+		Count   int                               `json:"count"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	wantResults := map[string]map[string]interface{}{
+		"did:veritas:alice":       {"status": "found", "document": map[string]interface{}{"id": "did:veritas:alice"}},
+		"did:veritas:unknown":     {"status": "not_found"},
+		"did:unreachable:example": {"status": "error", "error": "connection refused"},
+	}
+	if !reflect.DeepEqual(resp.Results, wantResults) {
+		t.Errorf("results = %v, want %v", resp.Results, wantResults)
+	}
+	if resp.Count != len(wantResults) {
+		t.Errorf("count = %d, want %d", resp.Count, len(wantResults))
+	}
+
+	if len(resp.Errors) != 2 || resp.Errors[0].Index != 3 || resp.Errors[1].Index != 4 {
+		t.Errorf("errors = %+v, want entries for indexes 3 and 4", resp.Errors)
+	}
+}
+
+func TestResolveBatchResolvesDuplicatesOnce(t *testing.T) {
+	h, _ := newTestRegistry()
+	resolver := newCountingResolver("did:test:alice")
+	h.RegisterResolver("test", resolver)
+
+	body := `{"dids":["did:test:alice","did:test:bob","did:test:alice","did:test:alice"]}`
+	rec := serve(t, h.HandleResolveBatch, http.MethodPost, "/api/v1/dids/resolve-batch", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	for did, want := range map[string]int{"did:test:alice": 1, "did:test:bob": 1} {
+		if got := resolver.count(did); got != want {
+			t.Errorf("%s resolved %d times, want %d", did, got, want)
+		}
+	}
+}
+
+// blockingResolver blocks every resolution until its context is done.
+type blockingResolver struct{}
+
+func (blockingResolver) Resolve(ctx context.Context, did string) (map[string]interface{}, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestResolveBatchHasOneDeadline(t *testing.T) {
+	h, _ := newTestRegistry()
+	h.RegisterResolver("slow", blockingResolver{})
+	h.batchTimeout = 50 * time.Millisecond
+	registerDID(t, h, "did:veritas:alice")
+
+	dids := []string{`"did:veritas:alice"`}
+	for i := 0; i < MaxResolveBatchSize-1; i++ {
+		dids = append(dids, fmt.Sprintf(`"did:slow:%d"`, i))
+	}
+
+	start := time.Now()
+	rec := serve(t, h.HandleResolveBatch, http.MethodPost, "/api/v1/dids/resolve-batch", `{"dids":[`+strings.Join(dids, ",")+`]}`)
+	// Serial resolution would take the timeout once per slow DID.
+	if elapsed := time.Since(start); elapsed > 20*h.batchTimeout {
+		t.Errorf("batch took %v, want about %v", elapsed, h.batchTimeout)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Results map[string]ResolveResult `json:"results"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Results["did:veritas:alice"].Status; got != "found" {
+		t.Errorf("local DID status = %q, want found", got)
+	}
+	if got := resp.Results["did:slow:0"].Status; got != "error" {
+		t.Errorf("timed-out DID status = %q, want error", got)
+	}
+}
+
+func TestResolveBatchRejectsBadRequests(t *testing.T) {
+	tooMany := make([]string, MaxResolveBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf(`"did:veritas:%d"`, i)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "empty list", body: `{"dids":[]}`},
+		{name: "missing list", body: `{}`},
+		{name: "over the limit", body: `{"dids":[` + strings.Join(tooMany, ",") + `]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestRegistry()
+			rec := serve(t, h.HandleResolveBatch, http.MethodPost, "/api/v1/dids/resolve-batch", tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestUseClockStampsRegistration(t *testing.T) {
+	h, fc := newTestRegistry()
+	fc.Advance(time.Hour)
+	registerDID(t, h, "did:veritas:alice")
+
+	rec := serve(t, h.HandleDidByID, http.MethodGet, "/api/v1/dids/did:veritas:alice", "")
+	var record DidRecord
+	if err := json.Unmarshal(rec.Body.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if !record.RegisteredAt.Equal(fc.Now()) {
+		t.Errorf("registered_at = %v, want %v", record.RegisteredAt, fc.Now())
+	}
+}
+
+func TestListPagesOnlyWhenAsked(t *testing.T) {
+	h, _ := newTestRegistry()
+	const total = httpjson.DefaultPageSize + 5
+	for i := 0; i < total; i++ {
+		registerDID(t, h, fmt.Sprintf("did:veritas:%03d", i))
+	}
+
+	tests := []struct {
+		name          string
+		target        string
+		wantPageCount int
+		wantNext      bool
+	}{
+		{name: "unpaged", target: "/api/v1/dids", wantPageCount: total},
+		{name: "page_size", target: "/api/v1/dids?page_size=10", wantPageCount: 10, wantNext: true},
+		{name: "page", target: "/api/v1/dids?page=1", wantPageCount: httpjson.DefaultPageSize, wantNext: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h.HandleDids, http.MethodGet, tt.target, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			var resp struct {
+				DIDs       []DidRecord `json:"dids"`
+				Count      int         `json:"count"`
+				PageCount  int         `json:"page_count"`
+				Total      int         `json:"total"`
+				NextCursor string      `json:"next_cursor"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.DIDs) != tt.wantPageCount || resp.PageCount != tt.wantPageCount {
+				t.Errorf("%d DIDs, page_count %d, want %d", len(resp.DIDs), resp.PageCount, tt.wantPageCount)
+			}
+			if resp.Count != total || resp.Total != total {
+				t.Errorf("count = %d, total = %d, want %d", resp.Count, resp.Total, total)
+			}
+			if (resp.NextCursor != "") != tt.wantNext {
+				t.Errorf("next_cursor = %q, want present: %v", resp.NextCursor, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+	"github.com/veritas-protocol/veritas/services/pkg/netguard"
+)
+
+// MaxDIDDocumentBytes is the largest DID document WebResolver accepts.
+const MaxDIDDocumentBytes = 256 << 10
+
+// ErrDIDNotFound is returned by a MethodResolver when the DID does not exist.
+var ErrDIDNotFound = errors.New("DID not found")
+
+// MethodResolver resolves DIDs of a single DID method to their documents.
+type MethodResolver interface {
+	Resolve(ctx context.Context, did string) (map[string]interface{}, error)
+}
+
+// DIDMethod returns the method of a DID of the form did:<method>:<id>.
+func DIDMethod(did string) (string, error) {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 || parts[0] != "did" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("malformed DID %q", did)
+	}
+	return parts[1], nil
+}
+
+// DefaultResolutionCacheTTL is how long CachingResolver keeps a resolution.
+const DefaultResolutionCacheTTL = 5 * time.Minute
+
+// MaxCachedResolutions bounds how many resolutions CachingResolver holds.
+// When full, expired entries are discarded first; if that frees no room,
+// new results are returned without being cached.
+const MaxCachedResolutions = 10000
+
+// CachingResolver caches the documents and not-found results of another
+// MethodResolver for a fixed TTL. Other errors are not cached.
+type CachingResolver struct {
+	resolver MethodResolver
+	ttl      time.Duration
+	max      int
+	clock    clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]resolutionCacheEntry
+}
+
+type resolutionCacheEntry struct {
+	doc       map[string]interface{}
+	err       error
+	expiresAt time.Time
+}
+
+// NewCachingResolver creates a CachingResolver in front of resolver.
+func NewCachingResolver(resolver MethodResolver, ttl time.Duration) *CachingResolver {
+	return &CachingResolver{
+		resolver: resolver,
+		ttl:      ttl,
+		max:      MaxCachedResolutions,
+		clock:    clock.New(),
+		entries:  make(map[string]resolutionCacheEntry),
+	}
+}
+
+// UseClock sets the time source used to expire cached resolutions.
+func (c *CachingResolver) UseClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+// Resolve returns the cached resolution of did while it is fresh and
+// resolves it otherwise.
+func (c *CachingResolver) Resolve(ctx context.Context, did string) (map[string]interface{}, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[did]
+	c.mu.Unlock()
+	if ok && c.clock.Now().Before(entry.expiresAt) {
+		return entry.doc, entry.err
+	}
+
+	doc, err := c.resolver.Resolve(ctx, did)
+	if err != nil && !errors.Is(err, ErrDIDNotFound) {
+		return nil, err
+	}
+
+	now := c.clock.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.max {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if _, exists := c.entries[did]; exists || len(c.entries) < c.max {
+		c.entries[did] = resolutionCacheEntry{doc: doc, err: err, expiresAt: now.Add(c.ttl)}
+	}
+	return doc, err
+}
+
+// WebResolver resolves did:web DIDs by fetching did.json over HTTPS, as
+// described by the did:web method specification.
+type WebResolver struct {
+	Client *http.Client
+	// Scheme is the URL scheme used to fetch documents; it defaults to
+	// "https" and is only overridden in tests.
+	Scheme string
+}
+
+// NewWebResolver creates a WebResolver with a bounded HTTP timeout whose
+// client refuses loopback, private and link-local addresses and does not
+// follow redirects.
+func NewWebResolver() *WebResolver {
+	return &WebResolver{
+		Client: netguard.NewClient(10 * time.Second),
+		Scheme: "https",
+	}
+}
+
+// Resolve fetches the DID document for a did:web DID.
+func (r *WebResolver) Resolve(ctx context.Context, did string) (map[string]interface{}, error) {
+	docURL, err := r.documentURL(did)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, docURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/did+json, application/json")
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", docURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrDIDNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: unexpected status %d", docURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxDIDDocumentBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", docURL, err)
+	}
+	if len(body) > MaxDIDDocumentBytes {
+		return nil, fmt.Errorf("fetch %s: document exceeds %d bytes", docURL, MaxDIDDocumentBytes)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", docURL, err)
+	}
+	if id, ok := doc["id"].(string); ok && id != did {
+		return nil, fmt.Errorf("document id %q does not match %s", id, did)
+	}
+
+	return doc, nil
+}
+
+// didWebHost matches the host segment of a did:web identifier: a domain
+// name, optionally followed by a port percent-encoded as %3A.
+var didWebHost = regexp.MustCompile(`^([A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*)(?:%3[Aa]([0-9]{1,5}))?$`)
+
+// didWebPathSegment matches a path segment of a did:web identifier. Percent
+// encoding is not accepted, so a segment cannot introduce a slash, query,
+// fragment or userinfo into the fetched URL.
+var didWebPathSegment = regexp.MustCompile(`^[A-Za-z0-9._~-]+$`)
+
+// documentURL maps did:web:<host>[:<path>...] to
+// <scheme>://<host>/<path>/did.json, or /.well-known/did.json without a path.
+// A port in the host is percent-encoded as %3A; no other percent-encoding is
+// accepted.
+func (r *WebResolver) documentURL(did string) (string, error) {
+	id, ok := strings.CutPrefix(did, "did:web:")
+	if !ok || id == "" {
+		return "", fmt.Errorf("not a did:web DID: %s", did)
+	}
+
+	segments := strings.Split(id, ":")
+	m := didWebHost.FindStringSubmatch(segments[0])
+	if m == nil {
+		return "", fmt.Errorf("malformed did:web host %q", segments[0])
+	}
+	host := m[1]
+	if m[2] != "" {
+		host += ":" + m[2]
+	}
+
+	for _, seg := range segments[1:] {
+		if !didWebPathSegment.MatchString(seg) || seg == "." || seg == ".." {
+			return "", fmt.Errorf("malformed did:web path segment %q", seg)
+		}
+	}
+
+	scheme := r.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+
+	path := "/.well-known/did.json"
+	if len(segments) > 1 {
+		path = "/" + strings.Join(segments[1:], "/") + "/did.json"
+	}
+	return scheme + "://" + host + path, nil
+}
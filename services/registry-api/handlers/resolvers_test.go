@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+	"github.com/veritas-protocol/veritas/services/pkg/netguard"
+)
+
+// newDIDWebServer serves did:web documents for the TLS test server's own
+// host, and returns the server with the DID prefix for it.
+func newDIDWebServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	var prefix string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/did.json":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": prefix})
+		case "/users/alice/did.json":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": prefix + ":users:alice"})
+		case "/users/mallory/did.json":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": prefix + ":users:alice"})
+		case "/users/huge/did.json":
+			fmt.Fprintf(w, `{"id":%q,"pad":"%s"}`, prefix+":users:huge", strings.Repeat("a", MaxDIDDocumentBytes))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix = "did:web:" + strings.ReplaceAll(u.Host, ":", "%3A")
+	return srv, prefix
+}
+
+func TestResolveDIDWeb(t *testing.T) {
+	srv, prefix := newDIDWebServer(t)
+
+	h, _ := newTestRegistry()
+	h.RegisterResolver("web", &WebResolver{Client: srv.Client(), Scheme: "https"})
+
+	tests := []struct {
+		name       string
+		did        string
+		wantStatus int
+	}{
+		{name: "host with port", did: prefix, wantStatus: http.StatusOK},
+		{name: "host with port and path", did: prefix + ":users:alice", wantStatus: http.StatusOK},
+		{name: "unknown path", did: prefix + ":users:bob", wantStatus: http.StatusNotFound},
+		{name: "document id mismatch", did: prefix + ":users:mallory", wantStatus: http.StatusBadGateway},
+		{name: "oversized document", did: prefix + ":users:huge", wantStatus: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The DID's own %3A must reach the resolver, so it is escaped.
+			rec := serve(t, h.HandleDidByID, http.MethodGet, "/api/v1/dids/"+url.PathEscape(tt.did), "")
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				DID      string                 `json:"did"`
+				Document map[string]interface{} `json:"document"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.DID != tt.did || resp.Document["id"] != tt.did {
+				t.Errorf("resolved %s to %+v", tt.did, resp)
+			}
+		})
+	}
+}
+
+func TestResolveLocalDID(t *testing.T) {
+	h, _ := newTestRegistry()
+	registerDID(t, h, "did:veritas:alice")
+
+	tests := []struct {
+		name       string
+		path       string
+		did        string
+		wantStatus int
+	}{
+		{name: "registered", path: "did:veritas:alice", did: "did:veritas:alice", wantStatus: http.StatusOK},
+		{name: "percent-encoded", path: "did%3Averitas%3Aalice", did: "did:veritas:alice", wantStatus: http.StatusOK},
+		{name: "not registered", path: "did:veritas:bob", wantStatus: http.StatusNotFound},
+		{name: "did:web is off by default", path: "did:web:example.com", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h.HandleDidByID, http.MethodGet, "/api/v1/dids/"+tt.path, "")
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var record DidRecord
+			if err := json.Unmarshal(rec.Body.Bytes(), &record); err != nil {
+				t.Fatal(err)
+			}
+			if record.DID != tt.did || record.RegisteredAt.IsZero() {
+				t.Errorf("record = %+v", record)
+			}
+		})
+	}
+}
+
+func TestNewWebResolverRefusesPrivateHosts(t *testing.T) {
+	_, prefix := newDIDWebServer(t)
+
+	_, err := NewWebResolver().Resolve(context.Background(), prefix)
+	if !errors.Is(err, netguard.ErrBlockedAddress) {
+		t.Errorf("Resolve(%s) error = %v, want ErrBlockedAddress", prefix, err)
+	}
+}
+
+func TestDIDWebDocumentURL(t *testing.T) {
+	tests := []struct {
+		did     string
+		want    string
+		wantErr bool
+	}{
+		{did: "did:web:example.com", want: "https://example.com/.well-known/did.json"},
+		{did: "did:web:example.com%3A8443", want: "https://example.com:8443/.well-known/did.json"},
+		{did: "did:web:example.com:users:alice", want: "https://example.com/users/alice/did.json"},
+		{did: "did:web:example.com:a%3Fb", wantErr: true},
+		{did: "did:web:example.com:a%2Fb", wantErr: true},
+		{did: "did:web:example.com:a%23b", wantErr: true},
+		{did: "did:web:example.com:..", wantErr: true},
+		{did: "did:web:user%40example.com", wantErr: true},
+		{did: "did:web:example.com%2Fpath", wantErr: true},
+		{did: "did:web:example.com%3Ahttp", wantErr: true},
+		{did: "did:web:example.com::users", wantErr: true},
+		{did: "did:web:", wantErr: true},
+	}
+
+	r := NewWebResolver()
+	for _, tt := range tests {
+		t.Run(tt.did, func(t *testing.T) {
+			got, err := r.documentURL(tt.did)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("documentURL = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("documentURL: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("documentURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingResolver resolves DIDs listed in docs, reports others as not
+// found, fails for did:test:broken, and counts calls per DID.
+type countingResolver struct {
+	mu    sync.Mutex
+	docs  map[string]map[string]interface{}
+	calls map[string]int
+}
+
+func newCountingResolver(dids ...string) *countingResolver {
+	r := &countingResolver{docs: make(map[string]map[string]interface{}), calls: make(map[string]int)}
+	for _, did := range dids {
+		r.docs[did] = map[string]interface{}{"id": did}
+	}
+	return r
+}
+
+func (r *countingResolver) Resolve(ctx context.Context, did string) (map[string]interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[did]++
+	if did == "did:test:broken" {
+		return nil, errors.New("connection refused")
+	}
+	doc, ok := r.docs[did]
+	if !ok {
+		return nil, ErrDIDNotFound
+	}
+	return doc, nil
+}
+
+func (r *countingResolver) count(did string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls[did]
+}
+
+func TestCachingResolver(t *testing.T) {
+	tests := []struct {
+		name      string
+		did       string
+		advance   time.Duration
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "found is cached", did: "did:test:alice", advance: DefaultResolutionCacheTTL - time.Second, wantCalls: 1},
+		{name: "not found is cached", did: "did:test:bob", advance: DefaultResolutionCacheTTL - time.Second, wantErr: ErrDIDNotFound, wantCalls: 1},
+		{name: "expired entry is resolved again", did: "did:test:alice", advance: DefaultResolutionCacheTTL, wantCalls: 2},
+		{name: "failures are not cached", did: "did:test:broken", wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := newCountingResolver("did:test:alice")
+			fc := clock.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+			c := NewCachingResolver(inner, DefaultResolutionCacheTTL)
+			c.UseClock(fc)
+
+			c.Resolve(context.Background(), tt.did)
+			fc.Advance(tt.advance)
+			_, err := c.Resolve(context.Background(), tt.did)
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got := inner.count(tt.did); got != tt.wantCalls {
+				t.Errorf("%d resolutions, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCachingResolverIsBounded(t *testing.T) {
+	inner := newCountingResolver("did:test:a", "did:test:b", "did:test:c")
+	fc := clock.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewCachingResolver(inner, DefaultResolutionCacheTTL)
+	c.UseClock(fc)
+	c.max = 2
+
+	for _, did := range []string{"did:test:a", "did:test:b", "did:test:c", "did:test:c"} {
+		if _, err := c.Resolve(context.Background(), did); err != nil {
+			t.Fatalf("Resolve(%s): %v", did, err)
+		}
+	}
+	if n := len(c.entries); n != 2 {
+		t.Errorf("%d entries cached, want 2", n)
+	}
+	if got := inner.count("did:test:c"); got != 2 {
+		t.Errorf("uncached DID resolved %d times, want 2", got)
+	}
+
+	// Expired entries make room again.
+	fc.Advance(DefaultResolutionCacheTTL)
+	c.Resolve(context.Background(), "did:test:c")
+	c.Resolve(context.Background(), "did:test:c")
+	if got := inner.count("did:test:c"); got != 3 {
+		t.Errorf("DID resolved %d times after expiry, want 3", got)
+	}
+}
@@ -3,11 +3,15 @@ package main
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"strconv"
 
+	"github.com/veritas-protocol/veritas/services/pkg/config"
+	"github.com/veritas-protocol/veritas/services/pkg/httpserver"
+	"github.com/veritas-protocol/veritas/services/pkg/lifecycle"
+	"github.com/veritas-protocol/veritas/services/pkg/selftest"
 	"github.com/veritas-protocol/veritas/services/registry-api/handlers"
 )
 
@@ -20,10 +24,21 @@ func main() {
 	}
 
 	registryHandler := handlers.NewRegistryHandler()
+	if v := os.Getenv("VERITAS_DID_WEB_ENABLED"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Fatalf("Invalid VERITAS_DID_WEB_ENABLED %q: %v", v, err)
+		}
+		if enabled {
+			registryHandler.RegisterResolver("web", handlers.NewCachingResolver(handlers.NewWebResolver(), handlers.DefaultResolutionCacheTTL))
+			log.Printf("did:web resolution enabled")
+		}
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/v1/dids", registryHandler.HandleDids)
 	mux.HandleFunc("/api/v1/dids/", registryHandler.HandleDidByID)
+	mux.HandleFunc("/api/v1/dids/resolve-batch", registryHandler.HandleResolveBatch)
 	mux.HandleFunc("/api/v1/schemas", registryHandler.HandleSchemas)
 	mux.HandleFunc("/api/v1/stats", registryHandler.HandleStats)
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -32,28 +47,55 @@ func main() {
 		w.Write([]byte(`{"status":"healthy","service":"registry-api"}`))
 	})
 
+	cfg := config.LoadFromEnv("registry-api")
+	cfg.Port = port
+
+	if selftest.Requested(os.Args[1:]) {
+		os.Exit(selftest.Main(os.Stdout, "registry-api",
+			selftest.ConfigCheck(cfg),
+			selftest.HandlerCheck("health", mux, "/health"),
+			selftest.HandlerCheck("did_store", mux, "/api/v1/dids"),
+			selftest.HandlerCheck("schema_store", mux, "/api/v1/schemas"),
+			selftest.HandlerCheck("stats", mux, "/api/v1/stats"),
+		))
+	}
+
 	addr := fmt.Sprintf(":%d", port)
-	server := &http.Server{Addr: addr, Handler: mux}
+	server, err := httpserver.New(addr, mux, cfg)
+	if err != nil {
+		log.Fatalf("Invalid server configuration: %v", err)
+	}
+
+	lc := lifecycle.New(slog.Default())
+	lc.Register("http-server", server.Start, server.Shutdown)
+	if err := lc.Start(); err != nil {
+		log.Fatalf("Startup failed: %v", err)
+	}
+
+	scheme := "http"
+	if server.TLS() {
+		scheme = "https"
+	}
+	log.Printf("Veritas Registry API starting on %s (%s)", addr, scheme)
+	log.Printf("Endpoints:")
+	log.Printf("  POST /api/v1/dids          — Register DID Document")
+	log.Printf("  GET  /api/v1/dids/:did     — Resolve DID")
+	log.Printf("  POST /api/v1/dids/resolve-batch — Resolve several DIDs")
+	log.Printf("  POST /api/v1/schemas       — Register schema")
+	log.Printf("  GET  /api/v1/schemas       — List schemas")
+	log.Printf("  GET  /api/v1/stats         — Registry stats")
+	log.Printf("  GET  /health")
 
 	go func() {
-		log.Printf("Veritas Registry API starting on %s", addr)
-		log.Printf("Endpoints:")
-		log.Printf("  POST /api/v1/dids          — Register DID Document")
-		log.Printf("  GET  /api/v1/dids/:did     — Resolve DID")
-		log.Printf("  POST /api/v1/schemas       — Register schema")
-		log.Printf("  GET  /api/v1/schemas       — List schemas")
-		log.Printf("  GET  /api/v1/stats         — Registry stats")
-		log.Printf("  GET  /health")
-
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := <-server.Err(); err != nil {
 			log.Fatalf("Server failed: %v", err)
 		}
 	}()
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	sig := <-sigCh
+	sig := lifecycle.WaitForSignal()
 	log.Printf("Received signal %v, shutting down...", sig)
-	server.Close()
+	if err := lc.Shutdown(lifecycle.DefaultShutdownTimeout); err != nil {
+		log.Printf("Shutdown incomplete: %v", err)
+	}
 	log.Println("Veritas Registry API stopped")
 }
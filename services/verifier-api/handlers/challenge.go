@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+)
+
+// DefaultChallengeTTL is how long an issued challenge remains usable.
+const DefaultChallengeTTL = 5 * time.Minute
+
+// minChallengeSweep is the number of used challenges held before expired
+// ones are first discarded.
+const minChallengeSweep = 1024
+
+// errInvalidChallenge is returned when a challenge is forged, expired or
+// already used.
+var errInvalidChallenge = errors.New("challenge is invalid, expired or already used")
+
+// ChallengeResponse is returned by POST /api/v1/challenge.
+type ChallengeResponse struct {
+	Challenge string    `json:"challenge"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// challengeStore issues stateless single-use challenges. A challenge is a
+// random nonce and its expiry, authenticated with a per-process HMAC key, so
+// issuing one stores nothing and anyone may request as many as they like.
+// Only consumed challenges are remembered, until they expire, to reject
+// reuse.
+type challengeStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	key     []byte
+	used    map[string]time.Time
+	sweepAt int
+	clock   clock.Clock
+}
+
+func newChallengeStore(ttl time.Duration, c clock.Clock) *challengeStore {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("verifier: failed to generate challenge key: %v", err))
+	}
+	return &challengeStore{
+		ttl:     ttl,
+		key:     key,
+		used:    make(map[string]time.Time),
+		sweepAt: minChallengeSweep,
+		clock:   c,
+	}
+}
+
+// issue creates a new challenge of the form nonce.expiry.mac.
+func (s *challengeStore) issue() (ChallengeResponse, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return ChallengeResponse{}, err
+	}
+
+	expiresAt := s.clock.Now().Add(s.ttl).Truncate(time.Second).UTC()
+	payload := hex.EncodeToString(nonce) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return ChallengeResponse{
+		Challenge: payload + "." + s.mac(payload),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// consume invalidates the challenge, returning errInvalidChallenge if it was
+// not issued by this store, has expired or was already used.
+func (s *challengeStore) consume(challenge string) error {
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 3 {
+		return errInvalidChallenge
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.mac(payload))) {
+		return errInvalidChallenge
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return errInvalidChallenge
+	}
+
+	now := s.clock.Now()
+	expiresAt := time.Unix(expiry, 0)
+	if !now.Before(expiresAt) {
+		return errInvalidChallenge
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, used := s.used[challenge]; used {
+		return errInvalidChallenge
+	}
+	if len(s.used) >= s.sweepAt {
+		for c, exp := range s.used {
+			if !now.Before(exp) {
+				delete(s.used, c)
+			}
+		}
+		s.sweepAt = 2 * len(s.used)
+		if s.sweepAt < minChallengeSweep {
+			s.sweepAt = minChallengeSweep
+		}
+	}
+	s.used[challenge] = expiresAt
+	return nil
+}
+
+func (s *challengeStore) mac(payload string) string {
+	m := hmac.New(sha256.New, s.key)
+	m.Write([]byte(payload))
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// HandleChallenge handles POST /api/v1/challenge.
+func (h *VerifierHandler) HandleChallenge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	resp, err := h.challenges.issue()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to generate challenge")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// consumeChallenge validates and invalidates the challenge a presentation or
+// proof carries, writing a 400 response and returning false if it is not
+// usable. A missing challenge is accepted; callers that bind the challenge
+// into what they verify must require it themselves.
+func (h *VerifierHandler) consumeChallenge(w http.ResponseWriter, challenge string) bool {
+	if challenge == "" {
+		return true
+	}
+	if err := h.challenges.consume(challenge); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
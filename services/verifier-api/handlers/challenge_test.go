@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// presentationBody returns a one-credential presentation carrying challenge.
+func presentationBody(challenge string) string {
+	return `{"verifiableCredential":[` + testCredential("vc-1", "") + `],"challenge":"` + challenge + `"}`
+}
+
+func TestChallengeIsSingleUse(t *testing.T) {
+	h, _ := newTestVerifier(t)
+	challenge := issueChallenge(t, h)
+
+	if rec := postJSON(t, h.HandleVerify, "/api/v1/verify", presentationBody(challenge)); rec.Code != http.StatusOK {
+		t.Fatalf("first use status = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := postJSON(t, h.HandleVerify, "/api/v1/verify", presentationBody(challenge)); rec.Code != http.StatusBadRequest {
+		t.Errorf("replay status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestChallengeExpiry(t *testing.T) {
+	tests := []struct {
+		name       string
+		advance    time.Duration
+		wantStatus int
+	}{
+		{name: "just before expiry", advance: DefaultChallengeTTL - time.Second, wantStatus: http.StatusOK},
+		{name: "at expiry", advance: DefaultChallengeTTL, wantStatus: http.StatusBadRequest},
+		{name: "after expiry", advance: DefaultChallengeTTL + time.Minute, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fc := newTestVerifier(t)
+			challenge := issueChallenge(t, h)
+			fc.Advance(tt.advance)
+
+			rec := postJSON(t, h.HandleVerify, "/api/v1/verify", presentationBody(challenge))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+}
+
+func TestChallengeOptionalForUnboundProofs(t *testing.T) {
+	h, _ := newTestVerifier(t)
+	ageRequest := createProofRequest(t, h, `{"proof_type":"age"}`)
+	kycRequest := createProofRequest(t, h, `{"proof_type":"kyc_level","requirements":{"min_level":2}}`)
+
+	issued := issueChallenge(t, h)
+	tampered := issued[:len(issued)-1] + "0"
+	if tampered == issued {
+		tampered = issued[:len(issued)-1] + "1"
+	}
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "presentation without a challenge",
+			handler:    h.HandleVerify,
+			path:       "/api/v1/verify",
+			body:       `{"verifiableCredential":[` + testCredential("vc-1", "") + `]}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "age proof without a challenge",
+			handler:    h.HandleVerifyProof,
+			path:       "/api/v1/verify-proof",
+			body:       `{"request_id":"` + ageRequest + `","proof_data":{"commitment":"c"}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "kyc_level proof without a challenge",
+			handler:    h.HandleVerifyProof,
+			path:       "/api/v1/verify-proof",
+			body:       `{"request_id":"` + kycRequest + `","proof_data":{"proof":{}}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "challenge not issued here",
+			handler:    h.HandleVerify,
+			path:       "/api/v1/verify",
+			body:       presentationBody("not-issued"),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "tampered challenge",
+			handler:    h.HandleVerify,
+			path:       "/api/v1/verify",
+			body:       presentationBody(tampered),
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := postJSON(t, tt.handler, tt.path, tt.body); rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+}
+
+func TestChallengeIssuanceHoldsNoState(t *testing.T) {
+	h, fc := newTestVerifier(t)
+
+	// Issuing is never refused, however many challenges are outstanding.
+	for i := 0; i < 2*minChallengeSweep; i++ {
+		issueChallenge(t, h)
+	}
+	if n := len(h.challenges.used); n != 0 {
+		t.Fatalf("%d challenges held after issuing, want 0", n)
+	}
+
+	// Used challenges are remembered until they expire, then discarded.
+	for i := 0; i < minChallengeSweep; i++ {
+		if rec := postJSON(t, h.HandleVerify, "/api/v1/verify", presentationBody(issueChallenge(t, h))); rec.Code != http.StatusOK {
+			t.Fatalf("verify status = %d: %s", rec.Code, rec.Body)
+		}
+	}
+	fc.Advance(DefaultChallengeTTL)
+	if rec := postJSON(t, h.HandleVerify, "/api/v1/verify", presentationBody(issueChallenge(t, h))); rec.Code != http.StatusOK {
+		t.Fatalf("verify status = %d: %s", rec.Code, rec.Body)
+	}
+	if n := len(h.challenges.used); n != 1 {
+		t.Errorf("%d used challenges held after expiry, want 1", n)
+	}
+}
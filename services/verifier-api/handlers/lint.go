@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/httpjson"
+)
+
+// Lint issue severities. Errors mark a credential as malformed under the
+// Veritas data model; warnings flag fields that are unusual but not fatal.
+// Lint is stricter than /api/v1/verify, which does not check issuanceDate or
+// type, so a credential with lint errors may still verify.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// BuiltinSchemas lists the credential schemas defined by the Veritas
+// specification.
+var BuiltinSchemas = []string{"kyc-basic-v1", "age-verification-v1", "residency-v1", "humanity-proof-v1"}
+
+// LintIssue is a structural problem found in a credential.
+type LintIssue struct {
+	Field    string `json:"field"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// LintResponse is returned by POST /api/v1/credentials/lint. Valid is false
+// if any issue has error severity.
+type LintResponse struct {
+	Valid  bool        `json:"valid"`
+	Issues []LintIssue `json:"issues"`
+}
+
+// HandleLint handles POST /api/v1/credentials/lint. It checks a credential's
+// structure only: signatures, revocation and expiry against the current time
+// are left to /api/v1/verify.
+func (h *VerifierHandler) HandleLint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req VerifyRequest
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
+		return
+	}
+
+	if len(req.Credential) == 0 {
+		writeError(w, http.StatusBadRequest, "credential is required")
+		return
+	}
+
+	issues := lintCredential(req.Credential)
+
+	valid := true
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			valid = false
+		}
+	}
+
+	writeJSON(w, http.StatusOK, LintResponse{Valid: valid, Issues: issues})
+}
+
+// lintCredential reports structural issues in cred. Required fields are the
+// ones verifyCredential checks for plus issuanceDate; dates accept both the
+// W3C names (issuanceDate, expirationDate) and the Veritas ones (issued_at,
+// expires_at).
+func lintCredential(cred map[string]interface{}) []LintIssue {
+	issues := make([]LintIssue, 0)
+	add := func(field, severity, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{Field: field, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if stringField(cred, "id", "credential_id") == "" {
+		add("id", LintWarning, "credential has no id, so it cannot be checked for revocation")
+	}
+
+	switch issuer := cred["issuer"].(type) {
+	case nil:
+		add("issuer", LintError, "issuer is required")
+	case string, map[string]interface{}:
+		if issuerID(issuer) == "" {
+			add("issuer", LintError, "issuer must be a DID or an object with an id")
+		}
+	default:
+		add("issuer", LintError, "issuer must be a DID or an object with an id")
+	}
+
+	if cred["subject"] == nil {
+		add("subject", LintError, "subject is required")
+	}
+
+	if claims, ok := cred["claims"]; !ok {
+		add("claims", LintError, "claims is required")
+	} else if _, ok := claims.(map[string]interface{}); !ok {
+		add("claims", LintError, "claims must be an object")
+	}
+
+	lintType(cred, add)
+
+	if _, present := firstPresent(cred, "issuanceDate", "issued_at"); !present {
+		add("issuanceDate", LintError, "issuanceDate is required")
+	}
+	issuedAt, issuedOK := lintDate(cred, add, "issuanceDate", "issued_at")
+	expiresAt, expiresOK := lintDate(cred, add, "expirationDate", "expires_at")
+	if issuedOK && expiresOK && !expiresAt.After(issuedAt) {
+		add("expirationDate", LintError, "expirationDate %s is not after issuanceDate %s",
+			expiresAt.Format(time.RFC3339), issuedAt.Format(time.RFC3339))
+	}
+
+	if cred["proof_signature"] == nil {
+		add("proof_signature", LintError, "proof_signature is required")
+	}
+
+	if id, _ := credentialSchemaRef(cred); id != "" && !isBuiltinSchema(id) {
+		add("credentialSchema", LintWarning, "schema %s is not a built-in Veritas schema", id)
+	}
+
+	return issues
+}
+
+// lintType checks that the credential's type is a non-empty string or list
+// of strings.
+func lintType(cred map[string]interface{}, add func(field, severity, format string, args ...interface{})) {
+	raw, present := firstPresent(cred, "type", "credential_type")
+	if !present {
+		add("type", LintWarning, "credential has no type")
+		return
+	}
+
+	switch types := raw.(type) {
+	case string:
+		if types == "" {
+			add("type", LintWarning, "type is empty")
+		}
+	case []interface{}:
+		if len(types) == 0 {
+			add("type", LintWarning, "type is empty")
+		}
+		for i, t := range types {
+			if s, ok := t.(string); !ok || s == "" {
+				add("type", LintError, "type[%d] must be a non-empty string", i)
+			}
+		}
+	default:
+		add("type", LintError, "type must be a string or a list of strings")
+	}
+}
+
+// lintDate parses the first of keys present as an RFC 3339 timestamp,
+// reporting a malformed value under the first key's name.
+func lintDate(cred map[string]interface{}, add func(field, severity, format string, args ...interface{}), keys ...string) (time.Time, bool) {
+	raw, present := firstPresent(cred, keys...)
+	if !present {
+		return time.Time{}, false
+	}
+
+	s, ok := raw.(string)
+	if !ok {
+		add(keys[0], LintError, "%s must be an RFC 3339 timestamp", keys[0])
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		add(keys[0], LintError, "%s %q is not an RFC 3339 timestamp", keys[0], s)
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// firstPresent returns the value of the first of keys set in m.
+func firstPresent(m map[string]interface{}, keys ...string) (interface{}, bool) {
+	for _, k := range keys {
+		if v, ok := m[k]; ok && v != nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func isBuiltinSchema(id string) bool {
+	for _, s := range BuiltinSchemas {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
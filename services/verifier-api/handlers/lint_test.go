@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestLintCredential(t *testing.T) {
+	// withFields returns testCredential with fields overridden; a nil value
+	// removes the field.
+	withFields := func(fields map[string]interface{}) string {
+		var cred map[string]interface{}
+		json.Unmarshal([]byte(testCredential("vc-1", "2027-01-01T00:00:00Z")), &cred)
+		cred["type"] = []string{"VerifiableCredential", "KYCCredential"}
+		for k, v := range fields {
+			if v == nil {
+				delete(cred, k)
+			} else {
+				cred[k] = v
+			}
+		}
+		b, _ := json.Marshal(cred)
+		return string(b)
+	}
+
+	tests := []struct {
+		name       string
+		credential string
+		wantValid  bool
+		wantIssues map[string]string
+	}{
+		{
+			name:       "well-formed credential",
+			credential: withFields(nil),
+			wantValid:  true,
+			wantIssues: map[string]string{},
+		},
+		{
+			name:       "missing issuanceDate",
+			credential: withFields(map[string]interface{}{"issuanceDate": nil}),
+			wantIssues: map[string]string{"issuanceDate": LintError},
+		},
+		{
+			name:       "Veritas field names",
+			credential: withFields(map[string]interface{}{"issuanceDate": nil, "expirationDate": nil, "issued_at": "2026-01-01T00:00:00Z", "expires_at": "2027-01-01T00:00:00Z"}),
+			wantValid:  true,
+			wantIssues: map[string]string{},
+		},
+		{
+			name:       "expiry before issuance",
+			credential: withFields(map[string]interface{}{"expirationDate": "2025-01-01T00:00:00Z"}),
+			wantIssues: map[string]string{"expirationDate": LintError},
+		},
+		{
+			name:       "non-string type entry",
+			credential: withFields(map[string]interface{}{"type": []interface{}{"VerifiableCredential", 7}}),
+			wantIssues: map[string]string{"type": LintError},
+		},
+		{
+			name:       "missing id and type",
+			credential: withFields(map[string]interface{}{"id": nil, "type": nil}),
+			wantValid:  true,
+			wantIssues: map[string]string{"id": LintWarning, "type": LintWarning},
+		},
+		{
+			name:       "custom schema",
+			credential: withFields(map[string]interface{}{"credentialSchema": map[string]interface{}{"id": "acme-employee-v1"}}),
+			wantValid:  true,
+			wantIssues: map[string]string{"credentialSchema": LintWarning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestVerifier(t)
+			rec := postJSON(t, h.HandleLint, "/api/v1/credentials/lint", `{"credential":`+tt.credential+`}`)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			var resp LintResponse
+			decodeJSON(t, rec, &resp)
+
+			if resp.Valid != tt.wantValid {
+				t.Errorf("valid = %v, want %v", resp.Valid, tt.wantValid)
+			}
+			got := map[string]string{}
+			for _, issue := range resp.Issues {
+				got[issue.Field] = issue.Severity
+			}
+			if len(got) != len(tt.wantIssues) {
+				t.Errorf("issues = %+v, want fields %v", resp.Issues, tt.wantIssues)
+			}
+			for field, severity := range tt.wantIssues {
+				if got[field] != severity {
+					t.Errorf("%s severity = %q, want %q (issues %+v)", field, got[field], severity, resp.Issues)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/veritas-protocol/veritas/services/pkg/zkp"
+)
+
+// createProofRequest creates a proof request and returns its ID.
+func createProofRequest(t *testing.T, h *VerifierHandler, body string) string {
+	t.Helper()
+	rec := postJSON(t, h.HandleProofRequest, "/api/v1/proof-request", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("proof-request status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp ProofRequestResponse
+	decodeJSON(t, rec, &resp)
+	return resp.RequestID
+}
+
+// kycLevelProof proves level >= minimum with a commitment attested by key
+// for challenge.
+func kycLevelProof(t *testing.T, key ed25519.PrivateKey, claim, challenge string, level, minimum int) zkp.ThresholdProof {
+	t.Helper()
+	secret, err := zkp.NewThresholdSecret(level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attestation, err := zkp.SignAttestation(zkp.Attestation{
+		CredentialID: "gw-vc-000001",
+		Issuer:       "did:veritas:key:gateway",
+		Subject:      "did:veritas:holder",
+		Claim:        claim,
+		Commitment:   secret.Commitment(),
+		Challenge:    challenge,
+	}, "did:veritas:key:gateway#attestation-key", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := zkp.ProveThreshold(secret, attestation, minimum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof
+}
+
+func TestVerifyKYCLevelProof(t *testing.T) {
+	gateway := newTestKey(t)
+	forger := newTestKey(t)
+
+	// Each proof is built for the challenge issued to the test case.
+	tests := []struct {
+		name       string
+		trustKey   bool
+		requestID  string
+		proof      func(challenge string) zkp.ThresholdProof
+		challenge  func(issued string) string
+		wantStatus int
+		wantValid  bool
+	}{
+		{
+			name:       "level 3 satisfies min_level 2",
+			trustKey:   true,
+			proof:      func(c string) zkp.ThresholdProof { return kycLevelProof(t, gateway, "kyc_level", c, 3, 2) },
+			wantStatus: http.StatusOK,
+			wantValid:  true,
+		},
+		{
+			name:       "proof for a lower minimum",
+			trustKey:   true,
+			proof:      func(c string) zkp.ThresholdProof { return kycLevelProof(t, gateway, "kyc_level", c, 3, 1) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "attested by an untrusted key",
+			trustKey:   true,
+			proof:      func(c string) zkp.ThresholdProof { return kycLevelProof(t, forger, "kyc_level", c, 3, 2) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "attestation for another claim",
+			trustKey:   true,
+			proof:      func(c string) zkp.ThresholdProof { return kycLevelProof(t, gateway, "age", c, 30, 2) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "attested for another challenge",
+			trustKey:   true,
+			proof:      func(c string) zkp.ThresholdProof { return kycLevelProof(t, gateway, "kyc_level", "captured", 3, 2) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "attestation without a challenge",
+			trustKey:   true,
+			proof:      func(c string) zkp.ThresholdProof { return kycLevelProof(t, gateway, "kyc_level", "", 3, 2) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "no trusted key configured",
+			proof:      func(c string) zkp.ThresholdProof { return kycLevelProof(t, gateway, "kyc_level", c, 3, 2) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing challenge",
+			trustKey:   true,
+			proof:      func(c string) zkp.ThresholdProof { return kycLevelProof(t, gateway, "kyc_level", c, 3, 2) },
+			challenge:  func(string) string { return "" },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown request ID",
+			trustKey:   true,
+			requestID:  "proof-req-999999",
+			proof:      func(c string) zkp.ThresholdProof { return kycLevelProof(t, gateway, "kyc_level", c, 3, 2) },
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestVerifier(t)
+			if tt.trustKey {
+				h.UseProofIssuerKey(gateway.Public().(ed25519.PublicKey))
+			}
+			requestID := createProofRequest(t, h, `{"proof_type":"kyc_level","requirements":{"min_level":2}}`)
+			if tt.requestID != "" {
+				requestID = tt.requestID
+			}
+			challenge := issueChallenge(t, h)
+			proof := tt.proof(challenge)
+			if tt.challenge != nil {
+				challenge = tt.challenge(challenge)
+			}
+
+			rec := postJSON(t, h.HandleVerifyProof, "/api/v1/verify-proof", kycLevelProofBody(requestID, proof, challenge))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Valid  bool   `json:"valid"`
+				Detail string `json:"detail"`
+			}
+			decodeJSON(t, rec, &resp)
+			if resp.Valid != tt.wantValid {
+				t.Errorf("valid = %v, want %v (detail %q)", resp.Valid, tt.wantValid, resp.Detail)
+			}
+			if !resp.Valid && resp.Detail == "" {
+				t.Error("rejected proof has no detail")
+			}
+		})
+	}
+}
+
+// kycLevelProofBody returns a verify-proof request body for a kyc_level proof.
+func kycLevelProofBody(requestID string, proof zkp.ThresholdProof, challenge string) string {
+	body, _ := json.Marshal(map[string]interface{}{
+		"request_id": requestID,
+		"proof_data": map[string]interface{}{"proof_type": "kyc_level", "proof": proof},
+		"challenge":  challenge,
+	})
+	return string(body)
+}
+
+func TestKYCLevelProofCannotBeReplayed(t *testing.T) {
+	gateway := newTestKey(t)
+	h, _ := newTestVerifier(t)
+	h.UseProofIssuerKey(gateway.Public().(ed25519.PublicKey))
+	requestID := createProofRequest(t, h, `{"proof_type":"kyc_level","requirements":{"min_level":2}}`)
+
+	challenge := issueChallenge(t, h)
+	proof := kycLevelProof(t, gateway, "kyc_level", challenge, 3, 2)
+
+	var first struct {
+		Valid bool `json:"valid"`
+	}
+	decodeJSON(t, postJSON(t, h.HandleVerifyProof, "/api/v1/verify-proof", kycLevelProofBody(requestID, proof, challenge)), &first)
+	if !first.Valid {
+		t.Fatal("original proof rejected")
+	}
+
+	// A captured proof sent again, with its own challenge or a fresh one,
+	// must not verify.
+	if rec := postJSON(t, h.HandleVerifyProof, "/api/v1/verify-proof", kycLevelProofBody(requestID, proof, challenge)); rec.Code != http.StatusBadRequest {
+		t.Errorf("replay with the used challenge: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	var replay struct {
+		Valid bool `json:"valid"`
+	}
+	decodeJSON(t, postJSON(t, h.HandleVerifyProof, "/api/v1/verify-proof", kycLevelProofBody(requestID, proof, issueChallenge(t, h))), &replay)
+	if replay.Valid {
+		t.Error("replay with a fresh challenge accepted")
+	}
+}
+
+func TestVerifyProofUsesRequestedProofType(t *testing.T) {
+	h, _ := newTestVerifier(t)
+	requestID := createProofRequest(t, h, `{"proof_type":"kyc_level","requirements":{"min_level":2}}`)
+
+	// Claiming another proof type must not route around the kyc_level checks.
+	body := `{"request_id":"` + requestID + `","proof_data":{"proof_type":"age","commitment":"anything"},"challenge":"` + issueChallenge(t, h) + `"}`
+	rec := postJSON(t, h.HandleVerifyProof, "/api/v1/verify-proof", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Valid bool `json:"valid"`
+	}
+	decodeJSON(t, rec, &resp)
+	if resp.Valid {
+		t.Error("structure-only proof accepted for a kyc_level request")
+	}
+}
+
+func TestProofRequestRequiresMinLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "min_level set", body: `{"proof_type":"kyc_level","requirements":{"min_level":2}}`, wantStatus: http.StatusCreated},
+		{name: "min_level missing", body: `{"proof_type":"kyc_level"}`, wantStatus: http.StatusBadRequest},
+		{name: "min_level not an integer", body: `{"proof_type":"kyc_level","requirements":{"min_level":1.5}}`, wantStatus: http.StatusBadRequest},
+		{name: "other proof types unaffected", body: `{"proof_type":"age"}`, wantStatus: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestVerifier(t)
+			rec := postJSON(t, h.HandleProofRequest, "/api/v1/proof-request", tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+}
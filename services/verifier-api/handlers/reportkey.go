@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/veritas-protocol/veritas/services/pkg/didkey"
+)
+
+// reportKeyID identifies the report signing key in the verifier's DID
+// document.
+const reportKeyID = verifierDID + "#report-key"
+
+// DIDDocument returns the verifier's DID document, which publishes the key
+// that verification reports are signed with.
+func (h *VerifierHandler) DIDDocument() didkey.Document {
+	return didkey.NewDocument(verifierDID, reportKeyID, h.PublicKey())
+}
+
+// HandleDIDDocument handles GET /api/v1/did-document.
+func (h *VerifierHandler) HandleDIDDocument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.DIDDocument())
+}
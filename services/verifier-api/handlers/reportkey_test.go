@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/veritas-protocol/veritas/services/pkg/didkey"
+)
+
+func newTestKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv
+}
+
+// publishedReportKey fetches the verifier's DID document over HTTP and
+// extracts the report key, as an independent client would.
+func publishedReportKey(t *testing.T, h *VerifierHandler, keyID string) ed25519.PublicKey {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.HandleDIDDocument(rec, httptest.NewRequest(http.MethodGet, "/api/v1/did-document", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("did-document status = %d", rec.Code)
+	}
+
+	var doc didkey.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	pub, err := doc.AssertionKey(keyID)
+	if err != nil {
+		t.Fatalf("AssertionKey: %v", err)
+	}
+	return pub
+}
+
+func TestVerifyReportSignature(t *testing.T) {
+	h := NewVerifierHandlerWithKey(newTestKey(t))
+
+	body := `{"credential":{"id":"vc-000001","issuer":"did:veritas:key:issuer-api","subject":"did:veritas:abc","claims":{"kyc_level":2},"proof_signature":"sig"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/verify/report", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.HandleVerifyReport(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var signed SignedVerificationReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &signed); err != nil {
+		t.Fatal(err)
+	}
+	pub := publishedReportKey(t, h, signed.KeyID)
+
+	forger := newTestKey(t)
+	forgedPayload, _ := json.Marshal(VerificationReport{CredentialID: "vc-000001", Valid: true, VerifierDID: verifierDID})
+
+	tests := []struct {
+		name    string
+		mutate  func(s *SignedVerificationReport)
+		wantErr bool
+	}{
+		{name: "untouched", mutate: func(s *SignedVerificationReport) {}},
+		{
+			name:   "convenience copy is not trusted",
+			mutate: func(s *SignedVerificationReport) { s.Report.Valid = !s.Report.Valid },
+		},
+		{
+			name: "tampered payload",
+			mutate: func(s *SignedVerificationReport) {
+				payload, _ := base64.StdEncoding.DecodeString(s.Payload)
+				payload = bytes.Replace(payload, []byte("vc-000001"), []byte("vc-999999"), 1)
+				s.Payload = base64.StdEncoding.EncodeToString(payload)
+			},
+			wantErr: true,
+		},
+		{
+			name: "signed by another key",
+			mutate: func(s *SignedVerificationReport) {
+				s.Payload = base64.StdEncoding.EncodeToString(forgedPayload)
+				s.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(forger, forgedPayload))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := signed
+			tt.mutate(&s)
+
+			report, err := VerifyReportSignature(s, pub)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("signature accepted, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyReportSignature: %v", err)
+			}
+			if report.CredentialID != "vc-000001" || report.VerifierDID != verifierDID || !report.Valid {
+				t.Errorf("verified report = %+v", report)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+)
+
+// DefaultRevocationMaxStaleness is how old a cached status list may be before
+// a revocation check fetches it again.
+const DefaultRevocationMaxStaleness = time.Minute
+
+// StatusList is an issuer's list of revoked credential IDs.
+type StatusList struct {
+	Revoked   []string  `json:"revoked"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StatusListFetcher retrieves an issuer's current status list.
+type StatusListFetcher interface {
+	FetchStatusList(ctx context.Context) (*StatusList, error)
+}
+
+// IssuerStatusListFetcher fetches the status list from the Veritas Issuer API.
+type IssuerStatusListFetcher struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewIssuerStatusListFetcher creates a fetcher for the issuer at baseURL.
+func NewIssuerStatusListFetcher(baseURL string) *IssuerStatusListFetcher {
+	return &IssuerStatusListFetcher{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// FetchStatusList fetches GET /api/v1/status-list.
+func (f *IssuerStatusListFetcher) FetchStatusList(ctx context.Context) (*StatusList, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.BaseURL+"/api/v1/status-list", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch status list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch status list: issuer returned %d", resp.StatusCode)
+	}
+
+	var list StatusList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("fetch status list: %w", err)
+	}
+	return &list, nil
+}
+
+// RevocationChecker answers revocation queries from a cached status list.
+// The list is served from the cache for up to maxStaleness after it was
+// fetched; callers needing a high-assurance answer can force a re-fetch.
+// A fetched list older than the cached one (by UpdatedAt) never replaces it,
+// so a lagging issuer replica cannot un-revoke a credential.
+type RevocationChecker struct {
+	fetcher      StatusListFetcher
+	maxStaleness time.Duration
+	clock        clock.Clock
+
+	mu        sync.Mutex
+	revoked   map[string]bool
+	updatedAt time.Time
+	fetchedAt time.Time
+	cached    bool
+}
+
+// NewRevocationChecker creates a RevocationChecker backed by fetcher.
+func NewRevocationChecker(fetcher StatusListFetcher, maxStaleness time.Duration) *RevocationChecker {
+	return &RevocationChecker{
+		fetcher:      fetcher,
+		maxStaleness: maxStaleness,
+		clock:        clock.New(),
+	}
+}
+
+// UseClock sets the time source used to age the cached status list.
+func (c *RevocationChecker) UseClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+// IsRevoked reports whether the credential is revoked. When fresh is true
+// the status list is re-fetched regardless of the cache's age.
+func (c *RevocationChecker) IsRevoked(ctx context.Context, credentialID string, fresh bool) (bool, error) {
+	c.mu.Lock()
+	if c.cached && !fresh && c.clock.Since(c.fetchedAt) < c.maxStaleness {
+		revoked := c.revoked[credentialID]
+		c.mu.Unlock()
+		return revoked, nil
+	}
+	c.mu.Unlock()
+
+	list, err := c.fetcher.FetchStatusList(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.cached || !list.UpdatedAt.Before(c.updatedAt) {
+		revoked := make(map[string]bool, len(list.Revoked))
+		for _, id := range list.Revoked {
+			revoked[id] = true
+		}
+		c.revoked = revoked
+		c.updatedAt = list.UpdatedAt
+	}
+	c.fetchedAt = c.clock.Now()
+	c.cached = true
+
+	return c.revoked[credentialID], nil
+}
+
+// checkNotRevoked fails if the credential is on its issuer's status list or
+// the list cannot be fetched.
+func checkNotRevoked(ctx context.Context, checker *RevocationChecker, credentialID string, fresh bool) VerifyCheck {
+	check := VerifyCheck{Name: "not_revoked", Passed: true}
+
+	revoked, err := checker.IsRevoked(ctx, credentialID, fresh)
+	if err != nil {
+		detail := fmt.Sprintf("could not check revocation status: %v", err)
+		check.Passed = false
+		check.Detail = &detail
+		return check
+	}
+
+	if revoked {
+		detail := fmt.Sprintf("credential %s has been revoked", credentialID)
+		check.Passed = false
+		check.Detail = &detail
+	}
+	return check
+}
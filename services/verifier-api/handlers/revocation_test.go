@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStatusListFetcher serves a replaceable status list and counts fetches.
+type fakeStatusListFetcher struct {
+	mu      sync.Mutex
+	list    StatusList
+	fetches int
+}
+
+func (f *fakeStatusListFetcher) FetchStatusList(ctx context.Context) (*StatusList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetches++
+	list := f.list
+	return &list, nil
+}
+
+func (f *fakeStatusListFetcher) set(list StatusList) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.list = list
+}
+
+func (f *fakeStatusListFetcher) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fetches
+}
+
+func TestRevocationCaching(t *testing.T) {
+	older := testNow.Add(-time.Hour)
+	revokedNow := StatusList{Revoked: []string{"vc-1"}, UpdatedAt: testNow}
+	revokedOlder := StatusList{Revoked: []string{"vc-1"}, UpdatedAt: older}
+
+	tests := []struct {
+		name        string
+		initial     StatusList
+		update      StatusList
+		advance     time.Duration
+		fresh       bool
+		wantRevoked bool
+		wantFetches int
+	}{
+		{
+			name:        "repeat inside the window is served from cache",
+			initial:     StatusList{UpdatedAt: older},
+			update:      revokedNow,
+			advance:     DefaultRevocationMaxStaleness - time.Second,
+			wantFetches: 1,
+		},
+		{
+			name:        "fresh re-fetches inside the window",
+			initial:     StatusList{UpdatedAt: older},
+			update:      revokedNow,
+			fresh:       true,
+			wantRevoked: true,
+			wantFetches: 2,
+		},
+		{
+			name:        "stale cache re-fetches",
+			initial:     StatusList{UpdatedAt: older},
+			update:      revokedNow,
+			advance:     DefaultRevocationMaxStaleness,
+			wantRevoked: true,
+			wantFetches: 2,
+		},
+		{
+			name:        "older list does not replace the cache",
+			initial:     revokedNow,
+			update:      StatusList{UpdatedAt: older},
+			fresh:       true,
+			wantRevoked: true,
+			wantFetches: 2,
+		},
+		{
+			name:        "list with the same timestamp replaces the cache",
+			initial:     revokedOlder,
+			update:      StatusList{UpdatedAt: older},
+			fresh:       true,
+			wantFetches: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fc := newTestVerifier(t)
+			fetcher := &fakeStatusListFetcher{list: tt.initial}
+			checker := NewRevocationChecker(fetcher, DefaultRevocationMaxStaleness)
+			checker.UseClock(fc)
+			h.UseRevocationChecker(checker)
+
+			body := `{"credential":` + testCredential("vc-1", "") + `}`
+			postJSON(t, h.HandleVerify, "/api/v1/verify", body)
+
+			fetcher.set(tt.update)
+			fc.Advance(tt.advance)
+			path := "/api/v1/verify"
+			if tt.fresh {
+				path += "?fresh=true"
+			}
+			rec := postJSON(t, h.HandleVerify, path, body)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			var resp VerifyResponse
+			decodeJSON(t, rec, &resp)
+
+			revoked := false
+			for _, check := range resp.Checks {
+				if check.Name == "not_revoked" && !check.Passed {
+					revoked = true
+				}
+			}
+			if revoked != tt.wantRevoked {
+				t.Errorf("revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+			if got := fetcher.count(); got != tt.wantFetches {
+				t.Errorf("fetches = %d, want %d", got, tt.wantFetches)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+)
+
+const (
+	// DefaultSchemaCacheTTL is how long resolved schemas are cached.
+	DefaultSchemaCacheTTL = 5 * time.Minute
+
+	// DefaultSchemaMissTTL is how long a schema the registry does not have
+	// is remembered as missing, so credentials naming an unknown schema do
+	// not each trigger a full scan of the registry.
+	DefaultSchemaMissTTL = 30 * time.Second
+
+	// MaxCachedSchemas bounds how many schemas and misses SchemaResolver
+	// holds, since credentials may name any schema. When full, expired
+	// entries are discarded first; if that frees no room, results are
+	// returned without being cached.
+	MaxCachedSchemas = 10000
+)
+
+// ErrSchemaNotFound is returned by a SchemaFetcher when no schema matches.
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// Schema is a credential schema definition published in the registry.
+type Schema struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Claims  []string `json:"claims"`
+}
+
+// SchemaFetcher retrieves a schema definition by ID and version. An empty
+// version matches any version. A missing schema is reported with an error
+// wrapping ErrSchemaNotFound.
+type SchemaFetcher interface {
+	FetchSchema(ctx context.Context, id, version string) (*Schema, error)
+}
+
+// RegistrySchemaFetcher fetches schemas from the Veritas Registry API.
+type RegistrySchemaFetcher struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewRegistrySchemaFetcher creates a fetcher for the registry at baseURL.
+func NewRegistrySchemaFetcher(baseURL string) *RegistrySchemaFetcher {
+	return &RegistrySchemaFetcher{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// FetchSchema walks the registry's schema list until it finds a match.
+func (f *RegistrySchemaFetcher) FetchSchema(ctx context.Context, id, version string) (*Schema, error) {
+	cursor := ""
+	for {
+		query := url.Values{"page_size": {"200"}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.BaseURL+"/api/v1/schemas?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := f.Client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetch schemas: %w", err)
+		}
+
+		var page struct {
+			Schemas    []Schema `json:"schemas"`
+			NextCursor string   `json:"next_cursor"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch schemas: registry returned %d", resp.StatusCode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fetch schemas: %w", err)
+		}
+
+		for i := range page.Schemas {
+			s := page.Schemas[i]
+			if s.ID == id && (version == "" || s.Version == version) {
+				return &s, nil
+			}
+		}
+
+		if page.NextCursor == "" {
+			return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, schemaKey(id, version))
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// SchemaResolver caches schema definitions by ID and version for a TTL, and
+// schemas the fetcher could not find for DefaultSchemaMissTTL.
+type SchemaResolver struct {
+	fetcher SchemaFetcher
+	ttl     time.Duration
+	missTTL time.Duration
+	max     int
+	clock   clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]schemaCacheEntry
+}
+
+// schemaCacheEntry holds a schema, or the not-found error for a miss.
+type schemaCacheEntry struct {
+	schema    *Schema
+	err       error
+	expiresAt time.Time
+}
+
+// NewSchemaResolver creates a SchemaResolver backed by fetcher.
+func NewSchemaResolver(fetcher SchemaFetcher, ttl time.Duration) *SchemaResolver {
+	return &SchemaResolver{
+		fetcher: fetcher,
+		ttl:     ttl,
+		missTTL: DefaultSchemaMissTTL,
+		max:     MaxCachedSchemas,
+		clock:   clock.New(),
+		entries: make(map[string]schemaCacheEntry),
+	}
+}
+
+// UseClock sets the time source used to expire cached schemas.
+func (r *SchemaResolver) UseClock(c clock.Clock) {
+	r.clock = c
+}
+
+// Resolve returns the schema, serving it or a recent miss from the cache
+// when a fresh entry exists and fetching it otherwise.
+func (r *SchemaResolver) Resolve(ctx context.Context, id, version string) (*Schema, error) {
+	key := schemaKey(id, version)
+
+	r.mu.Lock()
+	entry, ok := r.entries[key]
+	r.mu.Unlock()
+	if ok && r.clock.Now().Before(entry.expiresAt) {
+		return entry.schema, entry.err
+	}
+
+	schema, err := r.fetcher.FetchSchema(ctx, id, version)
+	ttl := r.ttl
+	if errors.Is(err, ErrSchemaNotFound) {
+		ttl = r.missTTL
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := r.clock.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) >= r.max {
+		for k, e := range r.entries {
+			if !now.Before(e.expiresAt) {
+				delete(r.entries, k)
+			}
+		}
+	}
+	if _, exists := r.entries[key]; exists || len(r.entries) < r.max {
+		r.entries[key] = schemaCacheEntry{schema: schema, err: err, expiresAt: now.Add(ttl)}
+	}
+
+	return schema, err
+}
+
+func schemaKey(id, version string) string {
+	if version == "" {
+		return id
+	}
+	return id + "@" + version
+}
+
+// credentialSchemaRef returns the schema ID and version a credential claims
+// to conform to, read from credentialSchema {id, version} or schema_id and
+// schema_version.
+func credentialSchemaRef(cred map[string]interface{}) (id, version string) {
+	if ref, ok := cred["credentialSchema"].(map[string]interface{}); ok {
+		return stringField(ref, "id"), stringField(ref, "version")
+	}
+	return stringField(cred, "schema_id"), stringField(cred, "schema_version")
+}
+
+// checkClaimsMatchSchema fails if the credential's claims are missing any
+// claim the schema requires.
+func checkClaimsMatchSchema(ctx context.Context, resolver *SchemaResolver, cred map[string]interface{}, id, version string) VerifyCheck {
+	check := VerifyCheck{Name: "claims_match_schema", Passed: true}
+
+	schema, err := resolver.Resolve(ctx, id, version)
+	if err != nil {
+		detail := fmt.Sprintf("could not resolve schema: %v", err)
+		check.Passed = false
+		check.Detail = &detail
+		return check
+	}
+
+	claims, _ := cred["claims"].(map[string]interface{})
+	var missing []string
+	for _, name := range schema.Claims {
+		if _, ok := claims[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		detail := fmt.Sprintf("missing claims required by %s: %s", schemaKey(schema.ID, schema.Version), strings.Join(missing, ", "))
+		check.Passed = false
+		check.Detail = &detail
+	}
+	return check
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+)
+
+// fakeSchemaFetcher serves schemas from memory and counts fetches.
+type fakeSchemaFetcher struct {
+	mu      sync.Mutex
+	schemas map[string]*Schema
+	fetches int
+}
+
+func (f *fakeSchemaFetcher) FetchSchema(ctx context.Context, id, version string) (*Schema, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetches++
+	if s, ok := f.schemas[schemaKey(id, version)]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, schemaKey(id, version))
+}
+
+func (f *fakeSchemaFetcher) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fetches
+}
+
+// schemaCredential returns a credential referencing kyc-basic@1.0 with claims.
+func schemaCredential(claims map[string]interface{}) string {
+	b, _ := json.Marshal(map[string]interface{}{
+		"credential": map[string]interface{}{
+			"id":               "vc-1",
+			"issuer":           "did:veritas:key:issuer-api",
+			"subject":          "did:veritas:holder",
+			"claims":           claims,
+			"proof_signature":  "sig",
+			"credentialSchema": map[string]interface{}{"id": "kyc-basic", "version": "1.0"},
+		},
+	})
+	return string(b)
+}
+
+func TestSchemaResolverCaching(t *testing.T) {
+	tests := []struct {
+		name        string
+		advance     time.Duration
+		wantFetches int
+	}{
+		{name: "second verification hits the cache", advance: time.Minute, wantFetches: 1},
+		{name: "entry at the TTL boundary re-fetches", advance: DefaultSchemaCacheTTL, wantFetches: 2},
+		{name: "expired entry re-fetches", advance: DefaultSchemaCacheTTL + time.Second, wantFetches: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fc := newTestVerifier(t)
+			fetcher := &fakeSchemaFetcher{schemas: map[string]*Schema{
+				"kyc-basic@1.0": {ID: "kyc-basic", Version: "1.0", Claims: []string{"kyc_level"}},
+			}}
+			resolver := NewSchemaResolver(fetcher, DefaultSchemaCacheTTL)
+			resolver.UseClock(fc)
+			h.UseSchemaResolver(resolver)
+
+			body := schemaCredential(map[string]interface{}{"kyc_level": 2})
+			for i := 0; i < 2; i++ {
+				if i == 1 {
+					fc.Advance(tt.advance)
+				}
+				var resp VerifyResponse
+				decodeJSON(t, postJSON(t, h.HandleVerify, "/api/v1/verify", body), &resp)
+				if !resp.Valid {
+					t.Fatalf("verification %d failed: %+v", i, resp.Checks)
+				}
+			}
+
+			if got := fetcher.count(); got != tt.wantFetches {
+				t.Errorf("fetches = %d, want %d", got, tt.wantFetches)
+			}
+		})
+	}
+}
+
+func TestSchemaResolverCachesMisses(t *testing.T) {
+	tests := []struct {
+		name        string
+		advance     time.Duration
+		wantFetches int
+	}{
+		{name: "repeated miss is cached", advance: DefaultSchemaMissTTL - time.Second, wantFetches: 1},
+		{name: "miss expires", advance: DefaultSchemaMissTTL, wantFetches: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc := clock.NewFakeClock(testNow)
+			fetcher := &fakeSchemaFetcher{}
+			resolver := NewSchemaResolver(fetcher, DefaultSchemaCacheTTL)
+			resolver.UseClock(fc)
+
+			for i := 0; i < 2; i++ {
+				if i == 1 {
+					fc.Advance(tt.advance)
+				}
+				if _, err := resolver.Resolve(context.Background(), "unknown", "1.0"); !errors.Is(err, ErrSchemaNotFound) {
+					t.Fatalf("Resolve error = %v, want ErrSchemaNotFound", err)
+				}
+			}
+
+			if got := fetcher.count(); got != tt.wantFetches {
+				t.Errorf("fetches = %d, want %d", got, tt.wantFetches)
+			}
+		})
+	}
+}
+
+// unreachableSchemaFetcher fails every fetch and counts them.
+type unreachableSchemaFetcher struct{ fetches int }
+
+func (f *unreachableSchemaFetcher) FetchSchema(ctx context.Context, id, version string) (*Schema, error) {
+	f.fetches++
+	return nil, errors.New("connection refused")
+}
+
+func TestSchemaResolverDoesNotCacheFailures(t *testing.T) {
+	fetcher := &unreachableSchemaFetcher{}
+	resolver := NewSchemaResolver(fetcher, DefaultSchemaCacheTTL)
+	resolver.UseClock(clock.NewFakeClock(testNow))
+
+	for i := 0; i < 2; i++ {
+		if _, err := resolver.Resolve(context.Background(), "kyc-basic", "1.0"); err == nil {
+			t.Fatal("Resolve succeeded, want error")
+		}
+	}
+	if fetcher.fetches != 2 {
+		t.Errorf("fetches = %d, want 2", fetcher.fetches)
+	}
+}
+
+func TestSchemaResolverIsBounded(t *testing.T) {
+	fc := clock.NewFakeClock(testNow)
+	resolver := NewSchemaResolver(&fakeSchemaFetcher{}, DefaultSchemaCacheTTL)
+	resolver.UseClock(fc)
+	resolver.max = 2
+
+	for _, id := range []string{"a", "b", "c"} {
+		resolver.Resolve(context.Background(), id, "")
+	}
+	if n := len(resolver.entries); n != 2 {
+		t.Errorf("%d entries cached, want 2", n)
+	}
+
+	fc.Advance(DefaultSchemaMissTTL)
+	resolver.Resolve(context.Background(), "c", "")
+	if _, ok := resolver.entries["c"]; !ok {
+		t.Error("expired entries did not make room")
+	}
+}
+
+func TestClaimsMatchSchema(t *testing.T) {
+	fetcher := &fakeSchemaFetcher{schemas: map[string]*Schema{
+		"kyc-basic@1.0": {ID: "kyc-basic", Version: "1.0", Claims: []string{"kyc_level", "country"}},
+	}}
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		want   bool
+	}{
+		{name: "all required claims", claims: map[string]interface{}{"kyc_level": 2, "country": "BR"}, want: true},
+		{name: "missing claim", claims: map[string]interface{}{"kyc_level": 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fc := newTestVerifier(t)
+			resolver := NewSchemaResolver(fetcher, DefaultSchemaCacheTTL)
+			resolver.UseClock(fc)
+			h.UseSchemaResolver(resolver)
+
+			rec := postJSON(t, h.HandleVerify, "/api/v1/verify", schemaCredential(tt.claims))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			var resp VerifyResponse
+			decodeJSON(t, rec, &resp)
+
+			for _, check := range resp.Checks {
+				if check.Name == "claims_match_schema" {
+					if check.Passed != tt.want {
+						t.Errorf("claims_match_schema passed = %v, want %v", check.Passed, tt.want)
+					}
+					return
+				}
+			}
+			t.Error("no claims_match_schema check reported")
+		})
+	}
+}
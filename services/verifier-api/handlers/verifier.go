@@ -2,22 +2,54 @@
 package handlers
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+	"github.com/veritas-protocol/veritas/services/pkg/httpjson"
+	"github.com/veritas-protocol/veritas/services/pkg/zkp"
 )
 
 // VerifyRequest represents a request to verify a credential presentation.
+// It carries either a single credential or a presentation bundling several
+// credentials in VerifiableCredential. Presentation credentials are decoded
+// one by one so a malformed entry is reported at its index. A presentation
+// may carry a Challenge issued by POST /api/v1/challenge, which is then
+// rejected if reused. Presentations carry no holder signature, so the
+// challenge is not bound to the credentials and does not prevent a captured
+// presentation from being replayed with a new challenge.
 type VerifyRequest struct {
-	Credential map[string]interface{} `json:"credential"`
+	Credential           map[string]interface{} `json:"credential,omitempty"`
+	Holder               string                 `json:"holder,omitempty"`
+	VerifiableCredential []json.RawMessage      `json:"verifiableCredential,omitempty"`
+	Challenge            string                 `json:"challenge,omitempty"`
 }
 
 // VerifyResponse is returned after verification.
 type VerifyResponse struct {
-	Valid  bool          `json:"valid"`
-	Checks []VerifyCheck `json:"checks"`
+	Valid       bool               `json:"valid"`
+	Checks      []VerifyCheck      `json:"checks"`
+	Credentials []CredentialResult `json:"credentials,omitempty"`
+}
+
+// CredentialResult is the verification outcome of one credential in a
+// presentation.
+type CredentialResult struct {
+	Index        int           `json:"index"`
+	CredentialID string        `json:"credential_id,omitempty"`
+	Valid        bool          `json:"valid"`
+	Checks       []VerifyCheck `json:"checks"`
+	Error        string        `json:"error,omitempty"`
 }
 
 // VerifyCheck is an individual verification check.
@@ -35,32 +67,120 @@ type ProofRequest struct {
 
 // ProofRequestResponse is returned after creating a proof request.
 type ProofRequestResponse struct {
-	RequestID  string    `json:"request_id"`
-	ProofType  string    `json:"proof_type"`
-	Status     string    `json:"status"`
-	CreatedAt  time.Time `json:"created_at"`
+	RequestID    string                 `json:"request_id"`
+	ProofType    string                 `json:"proof_type"`
+	Requirements map[string]interface{} `json:"requirements,omitempty"`
+	Status       string                 `json:"status"`
+	CreatedAt    time.Time              `json:"created_at"`
 }
 
-// VerifyProofRequest represents a proof to be verified.
+// VerifyProofRequest represents a proof to be verified. RequestID must name
+// a proof request created on this verifier. Challenge, issued by
+// POST /api/v1/challenge, is required for kyc_level proofs, whose
+// attestation must be signed for it; other proof types may omit it.
 type VerifyProofRequest struct {
 	RequestID string                 `json:"request_id"`
 	ProofData map[string]interface{} `json:"proof_data"`
+	Challenge string                 `json:"challenge,omitempty"`
+}
+
+// VerificationReport is an archivable record of a credential verification.
+type VerificationReport struct {
+	CredentialID string        `json:"credential_id"`
+	Issuer       string        `json:"issuer"`
+	Checks       []VerifyCheck `json:"checks"`
+	Valid        bool          `json:"valid"`
+	VerifierDID  string        `json:"verifier_did"`
+	Timestamp    time.Time     `json:"timestamp"`
+}
+
+// SignedVerificationReport carries the exact bytes that were signed in
+// Payload (base64 of the report's JSON encoding) so any client can verify the
+// signature without re-encoding the report. Report is a decoded copy for
+// convenience and is not covered by the signature. KeyID names the
+// verification method in the verifier's DID document whose key must be used
+// to check Signature.
+type SignedVerificationReport struct {
+	Report    VerificationReport `json:"report"`
+	Payload   string             `json:"payload"`
+	Signature string             `json:"signature"`
+	KeyID     string             `json:"key_id"`
 }
 
+// SupportedProofTypes lists the proof types this verifier accepts.
+var SupportedProofTypes = []string{"age", "residency", "kyc_level"}
+
+// verifierDID identifies this verifier in signed reports.
+const verifierDID = "did:veritas:key:verifier-api"
+
 // VerifierHandler handles verifier API endpoints.
 type VerifierHandler struct {
-	mu       sync.RWMutex
-	requests map[string]*ProofRequestResponse
-	counter  int
+	mu         sync.RWMutex
+	requests   map[string]*ProofRequestResponse
+	challenges *challengeStore
+	counter    int
+	signingKey ed25519.PrivateKey
+	proofKey   ed25519.PublicKey
+	schemas    *SchemaResolver
+	revocation *RevocationChecker
+	clock      clock.Clock
 }
 
-// NewVerifierHandler creates a new VerifierHandler.
+// NewVerifierHandler creates a new VerifierHandler with a freshly generated
+// report signing key. The key does not survive a restart, so reports it
+// signs cannot be checked later; use NewVerifierHandlerWithKey with a key
+// from didkey.LoadPrivateKey for archivable reports.
 func NewVerifierHandler() *VerifierHandler {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("verifier: failed to generate signing key: %v", err))
+	}
+	return NewVerifierHandlerWithKey(priv)
+}
+
+// NewVerifierHandlerWithKey creates a new VerifierHandler that signs
+// verification reports with the given key.
+func NewVerifierHandlerWithKey(key ed25519.PrivateKey) *VerifierHandler {
+	c := clock.New()
 	return &VerifierHandler{
-		requests: make(map[string]*ProofRequestResponse),
+		requests:   make(map[string]*ProofRequestResponse),
+		challenges: newChallengeStore(DefaultChallengeTTL, c),
+		signingKey: key,
+		clock:      c,
 	}
 }
 
+// UseClock sets the time source used for expiry checks, challenges and
+// timestamps. Call it before serving requests.
+func (h *VerifierHandler) UseClock(c clock.Clock) {
+	h.clock = c
+	h.challenges.clock = c
+}
+
+// UseSchemaResolver makes verification check credential claims against the
+// schema each credential references, resolved through resolver.
+func (h *VerifierHandler) UseSchemaResolver(resolver *SchemaResolver) {
+	h.schemas = resolver
+}
+
+// UseRevocationChecker makes verification fail for credentials that appear
+// on their issuer's status list, as reported by checker.
+func (h *VerifierHandler) UseRevocationChecker(checker *RevocationChecker) {
+	h.revocation = checker
+}
+
+// UseProofIssuerKey sets the key that kyc_level proofs must be attested
+// with: the gateway's attestation key, published in its DID document. Without
+// it every kyc_level proof is rejected.
+func (h *VerifierHandler) UseProofIssuerKey(pub ed25519.PublicKey) {
+	h.proofKey = pub
+}
+
+// PublicKey returns the public key that verification reports are signed with.
+func (h *VerifierHandler) PublicKey() ed25519.PublicKey {
+	return h.signingKey.Public().(ed25519.PublicKey)
+}
+
 // HandleVerify handles POST /api/v1/verify.
 func (h *VerifierHandler) HandleVerify(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -69,39 +189,72 @@ func (h *VerifierHandler) HandleVerify(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req VerifyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 
-	if len(req.Credential) == 0 {
-		writeError(w, http.StatusBadRequest, "credential is required")
+	if len(req.VerifiableCredential) > 0 {
+		if !h.consumeChallenge(w, req.Challenge) {
+			return
+		}
+		writeJSON(w, http.StatusOK, h.verifyPresentation(r.Context(), req.VerifiableCredential, freshRequested(r)))
 		return
 	}
 
-	// Check for required credential fields.
-	checks := []VerifyCheck{
-		{Name: "has_issuer", Passed: req.Credential["issuer"] != nil},
-		{Name: "has_subject", Passed: req.Credential["subject"] != nil},
-		{Name: "has_claims", Passed: req.Credential["claims"] != nil},
-		{Name: "has_proof", Passed: req.Credential["proof_signature"] != nil},
+	if len(req.Credential) == 0 {
+		writeError(w, http.StatusBadRequest, "credential or verifiableCredential is required")
+		return
 	}
 
-	allPassed := true
-	for _, c := range checks {
-		if !c.Passed {
-			allPassed = false
-		}
-	}
+	checks, valid := h.verifyCredential(r.Context(), req.Credential, freshRequested(r))
 
 	resp := VerifyResponse{
-		Valid:  allPassed,
+		Valid:  valid,
 		Checks: checks,
 	}
 
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// HandleVerifyReport handles POST /api/v1/verify/report.
+func (h *VerifierHandler) HandleVerifyReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req VerifyRequest
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
+		return
+	}
+
+	if len(req.Credential) == 0 {
+		writeError(w, http.StatusBadRequest, "credential is required")
+		return
+	}
+
+	checks, valid := h.verifyCredential(r.Context(), req.Credential, freshRequested(r))
+
+	report := VerificationReport{
+		CredentialID: stringField(req.Credential, "id", "credential_id"),
+		Issuer:       issuerID(req.Credential["issuer"]),
+		Checks:       checks,
+		Valid:        valid,
+		VerifierDID:  verifierDID,
+		Timestamp:    h.clock.Now().UTC(),
+	}
+
+	signed, err := h.signReport(report)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to sign report: %v", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, signed)
+}
+
 // HandleProofRequest handles POST /api/v1/proof-request.
 func (h *VerifierHandler) HandleProofRequest(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -110,8 +263,8 @@ func (h *VerifierHandler) HandleProofRequest(w http.ResponseWriter, r *http.Requ
 	}
 
 	var req ProofRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 
@@ -119,25 +272,46 @@ func (h *VerifierHandler) HandleProofRequest(w http.ResponseWriter, r *http.Requ
 		writeError(w, http.StatusBadRequest, "proof_type is required")
 		return
 	}
+	if req.ProofType == "kyc_level" {
+		if _, err := minLevelRequirement(req.Requirements); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
-	now := time.Now().UTC()
+	now := h.clock.Now().UTC()
 
 	h.mu.Lock()
 	h.counter++
 	requestID := fmt.Sprintf("proof-req-%06d", h.counter)
 	h.requests[requestID] = &ProofRequestResponse{
-		RequestID: requestID,
-		ProofType: req.ProofType,
-		Status:    "PENDING",
-		CreatedAt: now,
+		RequestID:    requestID,
+		ProofType:    req.ProofType,
+		Requirements: req.Requirements,
+		Status:       "PENDING",
+		CreatedAt:    now,
 	}
 	h.mu.Unlock()
 
 	writeJSON(w, http.StatusCreated, ProofRequestResponse{
-		RequestID: requestID,
-		ProofType: req.ProofType,
-		Status:    "PENDING",
-		CreatedAt: now,
+		RequestID:    requestID,
+		ProofType:    req.ProofType,
+		Requirements: req.Requirements,
+		Status:       "PENDING",
+		CreatedAt:    now,
+	})
+}
+
+// HandleProofTypes handles GET /api/v1/proof-types.
+func (h *VerifierHandler) HandleProofTypes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"proof_types": SupportedProofTypes,
+		"count":       len(SupportedProofTypes),
 	})
 }
 
@@ -149,8 +323,8 @@ func (h *VerifierHandler) HandleVerifyProof(w http.ResponseWriter, r *http.Reque
 	}
 
 	var req VerifyProofRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	if err := httpjson.Decode(w, r, &req); err != nil {
+		writeError(w, httpjson.StatusCode(err), err.Error())
 		return
 	}
 
@@ -159,6 +333,27 @@ func (h *VerifierHandler) HandleVerifyProof(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	h.mu.RLock()
+	request, exists := h.requests[req.RequestID]
+	h.mu.RUnlock()
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("proof request %q not found", req.RequestID))
+		return
+	}
+
+	if request.ProofType == "kyc_level" && req.Challenge == "" {
+		writeError(w, http.StatusBadRequest, "challenge is required for kyc_level proofs; obtain one from POST /api/v1/challenge")
+		return
+	}
+	if !h.consumeChallenge(w, req.Challenge) {
+		return
+	}
+
+	if request.ProofType == "kyc_level" {
+		h.verifyKYCLevelProof(w, req, request)
+		return
+	}
+
 	// Simplified proof verification — checks structure only.
 	valid := req.ProofData["commitment"] != nil || req.ProofData["proof_json"] != nil
 
@@ -169,10 +364,254 @@ func (h *VerifierHandler) HandleVerifyProof(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+// verifyKYCLevelProof checks a kyc_level threshold proof answering request.
+// The proof's commitment must be attested by the trusted proof issuer for
+// the challenge sent with the proof, and the proof must show at least the
+// min_level the request requires.
+func (h *VerifierHandler) verifyKYCLevelProof(w http.ResponseWriter, req VerifyProofRequest, request *ProofRequestResponse) {
+	raw, err := json.Marshal(req.ProofData["proof"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid proof: %v", err))
+		return
+	}
+	var proof zkp.ThresholdProof
+	if err := json.Unmarshal(raw, &proof); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid proof: %v", err))
+		return
+	}
+
+	// Requirements were validated when the request was created.
+	required, _ := minLevelRequirement(request.Requirements)
+
+	valid := false
+	var detail string
+	var attestation zkp.Attestation
+	switch {
+	case h.proofKey == nil:
+		detail = "no proof issuer key is configured"
+	case proof.Min < required:
+		detail = fmt.Sprintf("proof attests min_level %d, request requires %d", proof.Min, required)
+	default:
+		attestation, err = zkp.VerifyThreshold(proof, h.proofKey)
+		switch {
+		case err != nil:
+			detail = err.Error()
+		case attestation.Claim != "kyc_level":
+			detail = fmt.Sprintf("attestation covers claim %q, not kyc_level", attestation.Claim)
+		case attestation.Challenge != req.Challenge:
+			detail = "attestation was signed for a different challenge"
+		default:
+			valid = true
+		}
+	}
+
+	resp := map[string]interface{}{
+		"request_id": req.RequestID,
+		"proof_type": "kyc_level",
+		"valid":      valid,
+		"min_level":  proof.Min,
+		"status":     "VERIFIED",
+	}
+	if valid {
+		resp["credential_id"] = attestation.CredentialID
+		resp["subject"] = attestation.Subject
+	}
+	if detail != "" {
+		resp["detail"] = detail
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// minLevelRequirement reads the non-negative integer min_level a kyc_level
+// proof request requires.
+func minLevelRequirement(requirements map[string]interface{}) (int, error) {
+	v, ok := requirements["min_level"].(float64)
+	if !ok || v < 0 || v > zkp.MaxThresholdValue || v != float64(int(v)) {
+		return 0, fmt.Errorf("requirements.min_level must be an integer between 0 and %d", zkp.MaxThresholdValue)
+	}
+	return int(v), nil
+}
+
+func (h *VerifierHandler) signReport(report VerificationReport) (SignedVerificationReport, error) {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return SignedVerificationReport{}, err
+	}
+
+	return SignedVerificationReport{
+		Report:    report,
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(h.signingKey, payload)),
+		KeyID:     reportKeyID,
+	}, nil
+}
+
+// VerifyReportSignature checks that signed.Payload was signed by pub, which
+// the caller must obtain from the verifier's DID document (see
+// didkey.Document.AssertionKey), and returns the report decoded from the
+// signed payload. signed.Report is ignored.
+func VerifyReportSignature(signed SignedVerificationReport, pub ed25519.PublicKey) (VerificationReport, error) {
+	payload, err := base64.StdEncoding.DecodeString(signed.Payload)
+	if err != nil {
+		return VerificationReport{}, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil {
+		return VerificationReport{}, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	if !ed25519.Verify(pub, payload, sig) {
+		return VerificationReport{}, errors.New("report signature does not match")
+	}
+
+	var report VerificationReport
+	if err := json.Unmarshal(payload, &report); err != nil {
+		return VerificationReport{}, fmt.Errorf("invalid report payload: %w", err)
+	}
+	return report, nil
+}
+
+// verifyPresentation verifies every credential in a presentation. The
+// presentation is valid only if all of its credentials are.
+func (h *VerifierHandler) verifyPresentation(ctx context.Context, creds []json.RawMessage, fresh bool) VerifyResponse {
+	results := make([]CredentialResult, 0, len(creds))
+	var failed []string
+
+	for i, raw := range creds {
+		var cred map[string]interface{}
+		if err := json.Unmarshal(raw, &cred); err != nil || cred == nil {
+			msg := "credential must be a JSON object"
+			if err != nil {
+				msg = fmt.Sprintf("invalid credential: %v", err)
+			}
+			results = append(results, CredentialResult{Index: i, Checks: []VerifyCheck{}, Error: msg})
+			failed = append(failed, fmt.Sprintf("credential %d", i))
+			continue
+		}
+
+		checks, valid := h.verifyCredential(ctx, cred, fresh)
+		result := CredentialResult{
+			Index:        i,
+			CredentialID: stringField(cred, "id", "credential_id"),
+			Valid:        valid,
+			Checks:       checks,
+		}
+		results = append(results, result)
+
+		if !valid {
+			label := fmt.Sprintf("credential %d", i)
+			if result.CredentialID != "" {
+				label = fmt.Sprintf("credential %d (%s)", i, result.CredentialID)
+			}
+			failed = append(failed, label)
+		}
+	}
+
+	check := VerifyCheck{Name: "all_credentials_valid", Passed: len(failed) == 0}
+	if len(failed) > 0 {
+		detail := "failed: " + strings.Join(failed, ", ")
+		check.Detail = &detail
+	}
+
+	return VerifyResponse{
+		Valid:       check.Passed,
+		Checks:      []VerifyCheck{check},
+		Credentials: results,
+	}
+}
+
+// verifyCredential runs the structural and expiry checks on a credential,
+// plus the schema and revocation checks when those are configured, and
+// reports whether all of them passed. fresh forces the revocation status to
+// be re-fetched instead of read from the cache.
+func (h *VerifierHandler) verifyCredential(ctx context.Context, cred map[string]interface{}, fresh bool) ([]VerifyCheck, bool) {
+	checks := []VerifyCheck{
+		{Name: "has_issuer", Passed: cred["issuer"] != nil},
+		{Name: "has_subject", Passed: cred["subject"] != nil},
+		{Name: "has_claims", Passed: cred["claims"] != nil},
+		{Name: "has_proof", Passed: cred["proof_signature"] != nil},
+		checkNotExpired(cred, h.clock.Now().UTC()),
+	}
+
+	if h.schemas != nil {
+		if id, version := credentialSchemaRef(cred); id != "" {
+			checks = append(checks, checkClaimsMatchSchema(ctx, h.schemas, cred, id, version))
+		}
+	}
+
+	if h.revocation != nil {
+		if id := stringField(cred, "id", "credential_id"); id != "" {
+			checks = append(checks, checkNotRevoked(ctx, h.revocation, id, fresh))
+		}
+	}
+
+	allPassed := true
+	for _, c := range checks {
+		if !c.Passed {
+			allPassed = false
+		}
+	}
+
+	return checks, allPassed
+}
+
+// freshRequested reports whether the caller asked for fresh=true, bypassing
+// cached revocation status.
+func freshRequested(r *http.Request) bool {
+	fresh, _ := strconv.ParseBool(r.URL.Query().Get("fresh"))
+	return fresh
+}
+
+// checkNotExpired fails if the credential's expirationDate is malformed or
+// not after now. Credentials without an expirationDate never expire.
+func checkNotExpired(cred map[string]interface{}, now time.Time) VerifyCheck {
+	check := VerifyCheck{Name: "not_expired", Passed: true}
+
+	raw := stringField(cred, "expirationDate", "expires_at")
+	if raw == "" {
+		return check
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		detail := fmt.Sprintf("invalid expirationDate %q", raw)
+		check.Passed = false
+		check.Detail = &detail
+		return check
+	}
+
+	if !now.Before(expiresAt) {
+		detail := fmt.Sprintf("expired at %s", expiresAt.Format(time.RFC3339))
+		check.Passed = false
+		check.Detail = &detail
+	}
+	return check
+}
+
+// stringField returns the first non-empty string value among keys.
+func stringField(m map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := m[k].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// issuerID extracts the issuer DID, which may be a plain string or an
+// object with an "id" field.
+func issuerID(v interface{}) string {
+	switch issuer := v.(type) {
+	case string:
+		return issuer
+	case map[string]interface{}:
+		return stringField(issuer, "id")
+	}
+	return ""
+}
+
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+	httpjson.Write(w, status, data)
 }
 
 func writeError(w http.ResponseWriter, status int, message string) {
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veritas-protocol/veritas/services/pkg/clock"
+)
+
+// testNow is the fake time handlers are pinned to in tests.
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestVerifier returns a VerifierHandler driven by a fake clock.
+func newTestVerifier(t *testing.T) (*VerifierHandler, *clock.FakeClock) {
+	t.Helper()
+	fc := clock.NewFakeClock(testNow)
+	h := NewVerifierHandlerWithKey(newTestKey(t))
+	h.UseClock(fc)
+	return h, fc
+}
+
+func postJSON(t *testing.T, handler http.HandlerFunc, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func decodeJSON(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body, err)
+	}
+}
+
+// issueChallenge obtains a fresh challenge from the handler.
+func issueChallenge(t *testing.T, h *VerifierHandler) string {
+	t.Helper()
+	rec := postJSON(t, h.HandleChallenge, "/api/v1/challenge", "")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("challenge status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp ChallengeResponse
+	decodeJSON(t, rec, &resp)
+	return resp.Challenge
+}
+
+// testCredential returns a structurally complete credential as JSON, with
+// the given id and expirationDate (omitted when empty).
+func testCredential(id, expirationDate string) string {
+	cred := map[string]interface{}{
+		"id":              id,
+		"issuer":          "did:veritas:key:issuer-api",
+		"subject":         "did:veritas:holder",
+		"claims":          map[string]interface{}{"kyc_level": 2},
+		"proof_signature": "sig",
+		"issuanceDate":    "2026-01-01T00:00:00Z",
+	}
+	if expirationDate != "" {
+		cred["expirationDate"] = expirationDate
+	}
+	b, _ := json.Marshal(cred)
+	return string(b)
+}
+
+func TestVerifyPresentation(t *testing.T) {
+	tests := []struct {
+		name        string
+		creds       []string
+		wantValid   bool
+		wantFailed  []int
+		wantSummary string
+	}{
+		{
+			name:      "all valid",
+			creds:     []string{testCredential("vc-1", "2027-01-01T00:00:00Z"), testCredential("vc-2", "")},
+			wantValid: true,
+		},
+		{
+			name:        "one expired",
+			creds:       []string{testCredential("vc-1", "2027-01-01T00:00:00Z"), testCredential("vc-2", "2026-02-01T00:00:00Z")},
+			wantFailed:  []int{1},
+			wantSummary: "failed: credential 1 (vc-2)",
+		},
+		{
+			name:        "malformed entry",
+			creds:       []string{`"not an object"`, testCredential("vc-2", "")},
+			wantFailed:  []int{0},
+			wantSummary: "failed: credential 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestVerifier(t)
+			body := `{"verifiableCredential":[` + strings.Join(tt.creds, ",") + `],"challenge":"` + issueChallenge(t, h) + `"}`
+
+			rec := postJSON(t, h.HandleVerify, "/api/v1/verify", body)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			var resp VerifyResponse
+			decodeJSON(t, rec, &resp)
+
+			if resp.Valid != tt.wantValid {
+				t.Errorf("valid = %v, want %v", resp.Valid, tt.wantValid)
+			}
+			if len(resp.Credentials) != len(tt.creds) {
+				t.Fatalf("%d credential results, want %d", len(resp.Credentials), len(tt.creds))
+			}
+
+			failed := map[int]bool{}
+			for _, i := range tt.wantFailed {
+				failed[i] = true
+			}
+			for i, result := range resp.Credentials {
+				if result.Index != i {
+					t.Errorf("result %d has index %d", i, result.Index)
+				}
+				if result.Valid == failed[i] {
+					t.Errorf("credential %d valid = %v, want %v", i, result.Valid, !failed[i])
+				}
+			}
+
+			if tt.wantSummary != "" {
+				if len(resp.Checks) != 1 || resp.Checks[0].Detail == nil || *resp.Checks[0].Detail != tt.wantSummary {
+					t.Errorf("checks = %+v, want detail %q", resp.Checks, tt.wantSummary)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyPresentationNamesExpiredCheck(t *testing.T) {
+	h, _ := newTestVerifier(t)
+	body := `{"verifiableCredential":[` + testCredential("vc-1", "") + `,` + testCredential("vc-2", "2026-02-01T00:00:00Z") + `],"challenge":"` + issueChallenge(t, h) + `"}`
+
+	var resp VerifyResponse
+	decodeJSON(t, postJSON(t, h.HandleVerify, "/api/v1/verify", body), &resp)
+
+	for _, check := range resp.Credentials[1].Checks {
+		if check.Name == "not_expired" {
+			if check.Passed {
+				t.Error("not_expired passed for an expired credential")
+			}
+			return
+		}
+	}
+	t.Error("no not_expired check reported for credential 1")
+}
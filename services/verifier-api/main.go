@@ -3,11 +3,16 @@ package main
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"time"
 
+	"github.com/veritas-protocol/veritas/services/pkg/config"
+	"github.com/veritas-protocol/veritas/services/pkg/didkey"
+	"github.com/veritas-protocol/veritas/services/pkg/httpserver"
+	"github.com/veritas-protocol/veritas/services/pkg/lifecycle"
+	"github.com/veritas-protocol/veritas/services/pkg/selftest"
 	"github.com/veritas-protocol/veritas/services/verifier-api/handlers"
 )
 
@@ -19,11 +24,57 @@ func main() {
 		fmt.Sscanf(p, "%d", &port)
 	}
 
-	verifierHandler := handlers.NewVerifierHandler()
+	var verifierHandler *handlers.VerifierHandler
+	if keyFile := os.Getenv("VERITAS_SIGNING_KEY_FILE"); keyFile != "" {
+		key, err := didkey.LoadPrivateKey(keyFile)
+		if err != nil {
+			log.Fatalf("Invalid VERITAS_SIGNING_KEY_FILE: %v", err)
+		}
+		verifierHandler = handlers.NewVerifierHandlerWithKey(key)
+	} else {
+		log.Printf("VERITAS_SIGNING_KEY_FILE not set; signing reports with an ephemeral key")
+		verifierHandler = handlers.NewVerifierHandler()
+	}
+	if key := os.Getenv("VERITAS_PROOF_ISSUER_KEY"); key != "" {
+		pub, err := didkey.DecodeMultibase(key)
+		if err != nil {
+			log.Fatalf("Invalid VERITAS_PROOF_ISSUER_KEY: %v", err)
+		}
+		verifierHandler.UseProofIssuerKey(pub)
+	} else {
+		log.Printf("VERITAS_PROOF_ISSUER_KEY not set; kyc_level proofs will be rejected")
+	}
+	registryURL := os.Getenv("VERITAS_REGISTRY_URL")
+	if registryURL != "" {
+		verifierHandler.UseSchemaResolver(handlers.NewSchemaResolver(
+			handlers.NewRegistrySchemaFetcher(registryURL),
+			handlers.DefaultSchemaCacheTTL,
+		))
+	}
+	issuerURL := os.Getenv("VERITAS_ISSUER_URL")
+	if issuerURL != "" {
+		maxStaleness := handlers.DefaultRevocationMaxStaleness
+		if s := os.Getenv("VERITAS_REVOCATION_MAX_STALENESS"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				log.Fatalf("Invalid VERITAS_REVOCATION_MAX_STALENESS %q: %v", s, err)
+			}
+			maxStaleness = d
+		}
+		verifierHandler.UseRevocationChecker(handlers.NewRevocationChecker(
+			handlers.NewIssuerStatusListFetcher(issuerURL),
+			maxStaleness,
+		))
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/v1/verify", verifierHandler.HandleVerify)
+	mux.HandleFunc("/api/v1/verify/report", verifierHandler.HandleVerifyReport)
+	mux.HandleFunc("/api/v1/credentials/lint", verifierHandler.HandleLint)
 	mux.HandleFunc("/api/v1/proof-request", verifierHandler.HandleProofRequest)
+	mux.HandleFunc("/api/v1/proof-types", verifierHandler.HandleProofTypes)
+	mux.HandleFunc("/api/v1/did-document", verifierHandler.HandleDIDDocument)
+	mux.HandleFunc("/api/v1/challenge", verifierHandler.HandleChallenge)
 	mux.HandleFunc("/api/v1/verify-proof", verifierHandler.HandleVerifyProof)
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -31,26 +82,61 @@ func main() {
 		w.Write([]byte(`{"status":"healthy","service":"verifier-api"}`))
 	})
 
+	cfg := config.LoadFromEnv("verifier-api")
+	cfg.Port = port
+
+	if selftest.Requested(os.Args[1:]) {
+		checks := []selftest.Check{
+			selftest.ConfigCheck(cfg),
+			selftest.HandlerCheck("health", mux, "/health"),
+		}
+		if registryURL != "" {
+			checks = append(checks, selftest.UpstreamCheck("registry", registryURL))
+		}
+		if issuerURL != "" {
+			checks = append(checks, selftest.UpstreamCheck("issuer", issuerURL))
+		}
+		os.Exit(selftest.Main(os.Stdout, "verifier-api", checks...))
+	}
+
 	addr := fmt.Sprintf(":%d", port)
-	server := &http.Server{Addr: addr, Handler: mux}
+	server, err := httpserver.New(addr, mux, cfg)
+	if err != nil {
+		log.Fatalf("Invalid server configuration: %v", err)
+	}
+
+	lc := lifecycle.New(slog.Default())
+	lc.Register("http-server", server.Start, server.Shutdown)
+	if err := lc.Start(); err != nil {
+		log.Fatalf("Startup failed: %v", err)
+	}
+
+	scheme := "http"
+	if server.TLS() {
+		scheme = "https"
+	}
+	log.Printf("Veritas Verifier API starting on %s (%s)", addr, scheme)
+	log.Printf("Endpoints:")
+	log.Printf("  POST /api/v1/verify        — Verify a presentation")
+	log.Printf("  POST /api/v1/verify/report — Signed verification report")
+	log.Printf("  POST /api/v1/credentials/lint — Check credential structure")
+	log.Printf("  POST /api/v1/proof-request  — Create proof request")
+	log.Printf("  GET  /api/v1/proof-types    — List supported proof types")
+	log.Printf("  POST /api/v1/challenge      — Issue single-use challenge")
+	log.Printf("  POST /api/v1/verify-proof   — Verify proof response")
+	log.Printf("  GET  /api/v1/did-document   — Report signing key")
+	log.Printf("  GET  /health")
 
 	go func() {
-		log.Printf("Veritas Verifier API starting on %s", addr)
-		log.Printf("Endpoints:")
-		log.Printf("  POST /api/v1/verify        — Verify a presentation")
-		log.Printf("  POST /api/v1/proof-request  — Create proof request")
-		log.Printf("  POST /api/v1/verify-proof   — Verify proof response")
-		log.Printf("  GET  /health")
-
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := <-server.Err(); err != nil {
 			log.Fatalf("Server failed: %v", err)
 		}
 	}()
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	sig := <-sigCh
+	sig := lifecycle.WaitForSignal()
 	log.Printf("Received signal %v, shutting down...", sig)
-	server.Close()
+	if err := lc.Shutdown(lifecycle.DefaultShutdownTimeout); err != nil {
+		log.Printf("Shutdown incomplete: %v", err)
+	}
 	log.Println("Veritas Verifier API stopped")
 }